@@ -0,0 +1,167 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldDiff describes a single setting whose live value differs from the
+// desired one. Field is the JSON name of the setting.
+type FieldDiff struct {
+	Field   string
+	Live    interface{}
+	Desired interface{}
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %v -> %v", d.Field, d.Live, d.Desired)
+}
+
+// Diff compares the Openmix Application with the desired settings in opts
+// and returns the settings that differ. Leading and trailing whitespace in
+// appData is ignored, as NewDNSAppOpts strips it anyway.
+func (a *DNSApp) Diff(opts *DNSAppOpts) ([]FieldDiff, error) {
+	live := a.ToOpts()
+	live.AppData = strings.TrimSpace(live.AppData)
+	desired := *opts
+	desired.AppData = strings.TrimSpace(desired.AppData)
+	return diffOpts(&live, &desired)
+}
+
+// Diff compares the Platform with the desired settings in opts and returns
+// the settings that differ.
+func (p *Platform) Diff(opts *PlatformOpts) ([]FieldDiff, error) {
+	live := p.ToOpts()
+	return diffOpts(&live, opts)
+}
+
+// Diff compares the DNS Zone with the desired settings in opts and returns
+// the settings that differ.
+func (z *DNSZone) Diff(opts *DNSZoneOpts) ([]FieldDiff, error) {
+	live := z.ToOpts()
+	return diffOpts(&live, opts)
+}
+
+// Diff compares the DNS Record with the desired settings in opts and returns
+// the settings that differ. The record response is compared as JSON when
+// both sides hold valid JSON, so key order and spacing do not matter.
+func (r *DNSRecord) Diff(opts *DNSRecordOpts) ([]FieldDiff, error) {
+	live := r.ToOpts()
+	all, err := diffOpts(&live, opts)
+	if err != nil {
+		return nil, err
+	}
+	var result []FieldDiff
+	for _, current := range all {
+		if current.Field == "response" && equalJSONStrings(live.OMAppId, opts.OMAppId) {
+			continue
+		}
+		result = append(result, current)
+	}
+	return result, nil
+}
+
+// diffOpts compares two settings structs through their JSON representation,
+// so that only differences visible to the API are reported. Null values and
+// empty objects or arrays are considered equal. Fields that desired does not
+// mention at all, such as Extra fields carried over by ToOpts, are left out,
+// at any depth: nested objects only compare the keys of the desired object,
+// and array elements are matched by position.
+func diffOpts(live interface{}, desired interface{}) ([]FieldDiff, error) {
+	liveFields, err := toJSONFields(live)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields, err := toJSONFields(desired)
+	if err != nil {
+		return nil, err
+	}
+	var sortedKeys []string
+	for key := range desiredFields {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	var result []FieldDiff
+	for _, key := range sortedKeys {
+		liveValue := normalizeEmpty(liveFields[key])
+		desiredValue := normalizeEmpty(desiredFields[key])
+		if !matchesDesired(liveValue, desiredValue) {
+			result = append(result, FieldDiff{
+				Field:   key,
+				Live:    liveValue,
+				Desired: desiredValue,
+			})
+		}
+	}
+	return result, nil
+}
+
+func toJSONFields(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// matchesDesired reports whether the live JSON value holds everything the
+// desired one mentions. Keys of live objects that are missing from the
+// desired object are ignored.
+func matchesDesired(live interface{}, desired interface{}) bool {
+	live = normalizeEmpty(live)
+	switch typed := normalizeEmpty(desired).(type) {
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok && live != nil {
+			return false
+		}
+		for key, value := range typed {
+			if !matchesDesired(liveMap[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		liveSlice, ok := live.([]interface{})
+		if !ok || len(liveSlice) != len(typed) {
+			return false
+		}
+		for i := range typed {
+			if !matchesDesired(liveSlice[i], typed[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(live, typed)
+	}
+}
+
+func normalizeEmpty(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if len(typed) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(typed) == 0 {
+			return nil
+		}
+	}
+	return value
+}
+
+func equalJSONStrings(a string, b string) bool {
+	var parsedA, parsedB interface{}
+	if json.Unmarshal([]byte(a), &parsedA) != nil || json.Unmarshal([]byte(b), &parsedB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(parsedA, parsedB)
+}
@@ -0,0 +1,177 @@
+package itm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDNSAppDiff(t *testing.T) {
+	app := DNSApp{
+		Id:            123,
+		Name:          "foo",
+		AppData:       "foo app data\n",
+		AppCname:      "foo app cname",
+		Description:   "foo description",
+		FallbackCname: "fallback.foo.com",
+		Platforms:     []PlatformRef{NewPlatformRef(1, "foo.com")},
+		Type:          "RT_HTTP_PERFORMANCE",
+		Protocol:      "dns",
+		AvlThreshold:  80,
+		Version:       4,
+	}
+	// Same settings, with untrimmed app data
+	same := NewDNSAppOpts("foo", "foo app data", "foo description", "fallback.foo.com", []PlatformRef{NewPlatformRef(1, "foo.com")}, "RT_HTTP_PERFORMANCE", "dns", 80)
+	diffs, err := app.Diff(&same)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no differences; got %v", diffs)
+	}
+
+	changed := same
+	changed.AvlThreshold = 90
+	changed.Description = "bar description"
+	diffs, err = app.Diff(&changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []FieldDiff{
+		{Field: "availabilityThreshold", Live: float64(80), Desired: float64(90)},
+		{Field: "description", Live: "foo description", Desired: "bar description"},
+	}
+	if !reflect.DeepEqual(expected, diffs) {
+		t.Error(unexpectedValueString("diffs", expected, diffs))
+	}
+}
+
+func TestPlatformDiffEmptyMaps(t *testing.T) {
+	platform := Platform{
+		Id:          123,
+		Name:        "foo",
+		DisplayName: "foo",
+		Category:    &PlatformCategory{},
+	}
+	opts := PlatformOpts{
+		Name:        "foo",
+		DisplayName: "foo",
+	}
+	diffs, err := platform.Diff(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no differences; got %v", diffs)
+	}
+}
+
+func TestPlatformDiffTypedConfig(t *testing.T) {
+	platform := Platform{
+		Id:          123,
+		Name:        "foo",
+		DisplayName: "foo",
+		Category:    &PlatformCategory{Id: 1, Name: "Delivery Network"},
+		RadarOpts:   &RadarConfig{UsePublicData: true, HTTPEnabled: true},
+		SonarOpts:   &SonarConfig{Enabled: true, URL: "https://foo.com/health", Method: "GET"},
+	}
+	opts := platform.ToOpts()
+	opts.Category = &PlatformCategory{Id: 2}
+	opts.SonarOpts.URL = "https://foo.com/status"
+	diffs, err := platform.Diff(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []string
+	for _, current := range diffs {
+		fields = append(fields, current.Field)
+	}
+	if !reflect.DeepEqual([]string{"category", "sonarConfig"}, fields) {
+		t.Error(unexpectedValueString("fields", []string{"category", "sonarConfig"}, fields))
+	}
+}
+
+func TestDiffIgnoresNestedServerFields(t *testing.T) {
+	var app DNSApp
+	appJSON := `{"id":123,"name":"foo","appData":"foo app data","description":"foo description","type":"RT_HTTP_PERFORMANCE","protocol":"dns","availabilityThreshold":80,
+		"platforms":[{"id":12,"cname":"foo.com","platformName":"foo"}]}`
+	if err := json.Unmarshal([]byte(appJSON), &app); err != nil {
+		t.Fatal(err)
+	}
+	opts := NewDNSAppOpts("foo", "foo app data", "foo description", "", []PlatformRef{NewPlatformRef(12, "foo.com")}, "RT_HTTP_PERFORMANCE", "dns", 80)
+	diffs, err := app.Diff(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no differences; got %v", diffs)
+	}
+	opts.Platforms = []PlatformRef{NewPlatformRef(12, "bar.com")}
+	if diffs, _ := app.Diff(&opts); len(diffs) != 1 || diffs[0].Field != "platforms" {
+		t.Errorf("Expected a platforms difference; got %v", diffs)
+	}
+	opts.Platforms = []PlatformRef{NewPlatformRef(12, "foo.com"), NewPlatformRef(34, "bar.com")}
+	if diffs, _ := app.Diff(&opts); len(diffs) != 1 || diffs[0].Field != "platforms" {
+		t.Errorf("Expected a platforms difference; got %v", diffs)
+	}
+
+	var platform Platform
+	platformJSON := `{"id":123,"name":"foo","displayName":"foo","category":{"id":1,"name":"CDN"},
+		"sonarConfig":{"enabled":true,"url":"https://foo.com/health","method":"GET","marketId":3}}`
+	if err := json.Unmarshal([]byte(platformJSON), &platform); err != nil {
+		t.Fatal(err)
+	}
+	platformOpts := PlatformOpts{
+		Name:        "foo",
+		DisplayName: "foo",
+		Category:    &PlatformCategory{Id: 1},
+		SonarOpts:   &SonarConfig{Enabled: true, URL: "https://foo.com/health", Method: "GET"},
+	}
+	diffs, err = platform.Diff(&platformOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no differences; got %v", diffs)
+	}
+}
+
+func TestDNSRecordDiffResponse(t *testing.T) {
+	record := DNSRecord{
+		Id:            1234,
+		DNSZoneId:     123,
+		SubdomainName: "xyz",
+		OMAppId:       `{ "appId": 401 }`,
+		RecordType:    "CNAME",
+		TTL:           3600,
+	}
+	opts := NewDNSRecordOpts(123, "xyz", 401, "CNAME", 3600)
+	diffs, err := record.Diff(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no differences; got %v", diffs)
+	}
+	opts = NewDNSRecordOpts(123, "xyz", 402, "CNAME", 3600)
+	diffs, err = record.Diff(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 1 || diffs[0].Field != "response" {
+		t.Errorf("Expected a single response difference; got %v", diffs)
+	}
+}
+
+func TestDNSZoneToOpts(t *testing.T) {
+	zone := DNSZone{
+		Id:          123,
+		IsPrimary:   true,
+		DomainName:  "foo.domain.name",
+		Description: "foo description",
+	}
+	expected := NewDNSZoneOpts("foo.domain.name", "foo description")
+	if !reflect.DeepEqual(expected, zone.ToOpts()) {
+		t.Error(unexpectedValueString("zone opts", expected, zone.ToOpts()))
+	}
+}
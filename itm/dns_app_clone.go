@@ -0,0 +1,184 @@
+package itm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CloneOptions controls how CloneDNSApp copies an Openmix Application
+type CloneOptions struct {
+	// Name of the copy. The name of the source application is used when
+	// empty.
+	Name string
+	// PlatformNames renames platforms on the way, mapping a source platform
+	// name to the name of the target platform. Platforms not listed keep
+	// their name.
+	PlatformNames map[string]string
+	// SkipUnmapped drops the platforms that have no counterpart in the
+	// target account instead of failing
+	SkipUnmapped bool
+	// UpdateExisting updates the application of the same name in the target
+	// account, when there is one, instead of creating a new one
+	UpdateExisting bool
+	// Publish publishes the copy
+	Publish bool
+	// KeepExtra copies the fields of the application and of its platforms
+	// that the SDK does not model. They are dropped by default, as they may
+	// refer to the source account.
+	KeepExtra bool
+}
+
+// UnmappedPlatform is a platform of the source application that could not be
+// found in the target account
+type UnmappedPlatform struct {
+	Id     int
+	Name   string
+	Reason string
+}
+
+// CloneResult describes the outcome of CloneDNSApp
+type CloneResult struct {
+	// App is the copy, or nil when nothing was written
+	App *DNSApp
+	// Created is false when an existing application was updated
+	Created bool
+	// PlatformIds maps the source platform IDs to the target ones
+	PlatformIds map[int]int
+	Unmapped    []UnmappedPlatform
+}
+
+// UnmappedPlatformsError is returned by CloneDNSApp when some platforms have
+// no counterpart in the target account
+type UnmappedPlatformsError struct {
+	Platforms []UnmappedPlatform
+}
+
+func (e UnmappedPlatformsError) Error() string {
+	var messages []string
+	for _, current := range e.Platforms {
+		messages = append(messages, fmt.Sprintf("%d (%s): %s", current.Id, current.Name, current.Reason))
+	}
+	return "Unmapped platforms: " + strings.Join(messages, "; ")
+}
+
+// CloneDNSApp copies the Openmix Application id of src to dst, which usually
+// points to another account or environment. Platforms are matched by name,
+// after applying opts.PlatformNames, and their IDs replaced with those of the
+// target account. Unless opts.SkipUnmapped is set, nothing is written when a
+// platform cannot be mapped: the returned CloneResult lists the unmapped
+// platforms and the error is an *UnmappedPlatformsError. The same error is
+// returned when skipping leaves no platform at all, and the settings are
+// validated before anything is written. With opts.UpdateExisting and
+// opts.Publish, an existing application is published even when its settings
+// are already up to date. Unmodeled fields are only copied with
+// opts.KeepExtra.
+func CloneDNSApp(src *Client, id int, dst *Client, opts CloneOptions) (*CloneResult, error) {
+	app, err := src.DNSApps.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	sourcePlatforms, err := src.Platform.List(PlatformIdIn(platformRefIds(app.Platforms)...))
+	if err != nil {
+		return nil, err
+	}
+	targetPlatforms, err := dst.Platform.List()
+	if err != nil {
+		return nil, err
+	}
+	result := &CloneResult{PlatformIds: make(map[int]int)}
+	appOpts := app.ToOpts()
+	if opts.Name != "" {
+		appOpts.Name = opts.Name
+	}
+	if !opts.KeepExtra {
+		appOpts.Extra = nil
+	}
+	appOpts.Platforms = nil
+	for _, ref := range app.Platforms {
+		targetId, unmapped := mapPlatform(ref.Id, sourcePlatforms, targetPlatforms, opts.PlatformNames)
+		if unmapped != nil {
+			result.Unmapped = append(result.Unmapped, *unmapped)
+			continue
+		}
+		result.PlatformIds[ref.Id] = targetId
+		mapped := ref.copy()
+		mapped.Id = targetId
+		if !opts.KeepExtra {
+			mapped.Extra = nil
+		}
+		appOpts.Platforms = append(appOpts.Platforms, mapped)
+	}
+	if len(result.Unmapped) > 0 && !opts.SkipUnmapped {
+		return result, &UnmappedPlatformsError{Platforms: result.Unmapped}
+	}
+	if len(app.Platforms) > 0 && len(appOpts.Platforms) == 0 {
+		return result, &UnmappedPlatformsError{Platforms: result.Unmapped}
+	}
+	if err := appOpts.Validate(); err != nil {
+		return result, err
+	}
+	if opts.UpdateExisting {
+		existing, err := dst.DNSApps.GetByName(appOpts.Name)
+		if err == nil {
+			var changed bool
+			result.App, changed, err = dst.DNSApps.UpdateIfChanged(existing.Id, &appOpts, opts.Publish)
+			if err == nil && !changed && opts.Publish {
+				// Nothing was written, so the publish flag was not sent
+				result.App, err = dst.DNSApps.Publish(existing.Id)
+			}
+			if err != nil {
+				return result, err
+			}
+			return result, nil
+		}
+		if _, notFound := err.(*NotFoundError); !notFound {
+			return result, err
+		}
+	}
+	result.App, err = dst.DNSApps.Create(&appOpts, opts.Publish)
+	if err != nil {
+		return result, err
+	}
+	result.Created = true
+	return result, nil
+}
+
+// mapPlatform finds the target platform matching the source platform
+// sourceId by name
+func mapPlatform(sourceId int, sourcePlatforms []Platform, targetPlatforms []Platform, renames map[string]string) (int, *UnmappedPlatform) {
+	var source *Platform
+	for i := range sourcePlatforms {
+		if sourcePlatforms[i].Id == sourceId {
+			source = &sourcePlatforms[i]
+			break
+		}
+	}
+	if source == nil {
+		return 0, &UnmappedPlatform{Id: sourceId, Reason: "not found in the source account"}
+	}
+	name := source.Name
+	if renamed, ok := renames[name]; ok {
+		name = renamed
+	}
+	var matches []int
+	for _, current := range targetPlatforms {
+		if current.Name == name {
+			matches = append(matches, current.Id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return 0, &UnmappedPlatform{Id: sourceId, Name: source.Name, Reason: fmt.Sprintf("no platform named %q in the target account", name)}
+	case 1:
+		return matches[0], nil
+	}
+	return 0, &UnmappedPlatform{Id: sourceId, Name: source.Name, Reason: fmt.Sprintf("%d platforms named %q in the target account", len(matches), name)}
+}
+
+func platformRefIds(refs []PlatformRef) []int {
+	result := make([]int, len(refs))
+	for i, current := range refs {
+		result[i] = current.Id
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func setupCloneSource() func() {
+	teardown := setup()
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":123,"name":"foo","cname":"2-01-abcd-0001.cdx.cedexis.net","type":"RT_HTTP_PERFORMANCE","protocol":"dns",
+			"fallbackCname":"fallback.foo.com","availabilityThreshold":80,"version":7,"customField":"kept",
+			"platforms":[{"id":1,"cname":"a.foo.com","weight":3,"platformName":"cdn-a"},{"id":2,"cname":"b.foo.com"}]}`)
+	})
+	mux.HandleFunc("/v2/config/platforms.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[{"id":1,"name":"cdn-a"},{"id":2,"name":"cdn-b"},{"id":3,"name":"cdn-c"}]`)
+	})
+	return teardown
+}
+
+func newCloneTarget(platforms string, existingApps string, created *DNSAppOpts) (*Client, *http.ServeMux, func()) {
+	targetMux := http.NewServeMux()
+	targetServer := httptest.NewServer(targetMux)
+	targetURL, _ := url.Parse(targetServer.URL)
+	target, _ := NewClient(BaseURL(targetURL))
+	targetMux.HandleFunc("/v2/config/platforms.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, platforms)
+	})
+	targetMux.HandleFunc("/v2/config/applications/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, existingApps)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(created)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":999,"name":%q,"version":1}`, created.Name)
+	})
+	return target, targetMux, targetServer.Close
+}
+
+func TestCloneDNSApp(t *testing.T) {
+	teardown := setupCloneSource()
+	defer teardown()
+	var created DNSAppOpts
+	target, _, targetTeardown := newCloneTarget(`[{"id":10,"name":"cdn-a"},{"id":20,"name":"cdn-b-prod"}]`, `[]`, &created)
+	defer targetTeardown()
+	result, err := CloneDNSApp(client, 123, target, CloneOptions{
+		Name:          "foo-prod",
+		PlatformNames: map[string]string{"cdn-b": "cdn-b-prod"},
+		Publish:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("id", 999, result.App.Id); err != nil {
+		t.Error(err)
+	}
+	if !result.Created {
+		t.Error("Expected the application to be created")
+	}
+	if !reflect.DeepEqual(map[int]int{1: 10, 2: 20}, result.PlatformIds) {
+		t.Error(unexpectedValueString("platform IDs", map[int]int{1: 10, 2: 20}, result.PlatformIds))
+	}
+	if err := testValues("name", "foo-prod", created.Name); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("platform ids", "[10 20]", fmt.Sprint(platformRefIds(created.Platforms))); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("weight", 3, *created.Platforms[0].Weight); err != nil {
+		t.Error(err)
+	}
+	if len(created.Extra) != 0 || len(created.Platforms[0].Extra) != 0 {
+		t.Errorf("Did not expect unmodeled fields to be copied, got %v and %v", created.Extra, created.Platforms[0].Extra)
+	}
+}
+
+func TestCloneDNSAppKeepExtra(t *testing.T) {
+	teardown := setupCloneSource()
+	defer teardown()
+	var created DNSAppOpts
+	target, _, targetTeardown := newCloneTarget(`[{"id":10,"name":"cdn-a"},{"id":20,"name":"cdn-b"}]`, `[]`, &created)
+	defer targetTeardown()
+	if _, err := CloneDNSApp(client, 123, target, CloneOptions{KeepExtra: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("custom field", `"kept"`, string(created.Extra["customField"])); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("platform name", `"cdn-a"`, string(created.Platforms[0].Extra["platformName"])); err != nil {
+		t.Error(err)
+	}
+	if _, ok := created.Extra["version"]; ok {
+		t.Error("Did not expect the version to be copied")
+	}
+}
+
+func TestCloneDNSAppUnmapped(t *testing.T) {
+	teardown := setupCloneSource()
+	defer teardown()
+	var created DNSAppOpts
+	target, _, targetTeardown := newCloneTarget(`[{"id":10,"name":"cdn-a"}]`, `[]`, &created)
+	defer targetTeardown()
+	result, err := CloneDNSApp(client, 123, target, CloneOptions{})
+	unmappedErr, ok := err.(*UnmappedPlatformsError)
+	if !ok {
+		t.Fatalf("Expected *UnmappedPlatformsError, got %v", err)
+	}
+	expected := []UnmappedPlatform{{Id: 2, Name: "cdn-b", Reason: `no platform named "cdn-b" in the target account`}}
+	if !reflect.DeepEqual(expected, unmappedErr.Platforms) {
+		t.Error(unexpectedValueString("unmapped", expected, unmappedErr.Platforms))
+	}
+	if result.App != nil {
+		t.Error("Expected nothing to be written")
+	}
+	if created.Name != "" {
+		t.Error("Did not expect a create request")
+	}
+
+	result, err = CloneDNSApp(client, 123, target, CloneOptions{SkipUnmapped: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("platform ids", "[10]", fmt.Sprint(platformRefIds(created.Platforms))); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("unmapped", 1, len(result.Unmapped)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCloneDNSAppUpdateExisting(t *testing.T) {
+	teardown := setupCloneSource()
+	defer teardown()
+	var created DNSAppOpts
+	target, targetMux, targetTeardown := newCloneTarget(`[{"id":10,"name":"cdn-a"},{"id":20,"name":"cdn-b"}]`, `[{"id":555,"name":"foo"}]`, &created)
+	defer targetTeardown()
+	var updated DNSAppOpts
+	targetMux.HandleFunc("/v2/config/applications/dns.json/555", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == "PUT" {
+			json.NewDecoder(r.Body).Decode(&updated)
+			fmt.Fprint(w, `{"id":555,"name":"foo","version":2}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":555,"name":"foo","version":1}`)
+	})
+	result, err := CloneDNSApp(client, 123, target, CloneOptions{UpdateExisting: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created || created.Name != "" {
+		t.Error("Did not expect a create request")
+	}
+	if err := testValues("id", 555, result.App.Id); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("platform ids", "[10 20]", fmt.Sprint(platformRefIds(updated.Platforms))); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCloneDNSAppNoPlatformLeft(t *testing.T) {
+	teardown := setupCloneSource()
+	defer teardown()
+	var created DNSAppOpts
+	target, _, targetTeardown := newCloneTarget(`[{"id":30,"name":"cdn-c"}]`, `[]`, &created)
+	defer targetTeardown()
+	result, err := CloneDNSApp(client, 123, target, CloneOptions{SkipUnmapped: true})
+	if _, ok := err.(*UnmappedPlatformsError); !ok {
+		t.Fatalf("Expected *UnmappedPlatformsError, got %v", err)
+	}
+	if err := testValues("unmapped", 2, len(result.Unmapped)); err != nil {
+		t.Error(err)
+	}
+	if created.Name != "" {
+		t.Error("Did not expect a create request")
+	}
+}
+
+func TestCloneDNSAppPublishUnchanged(t *testing.T) {
+	teardown := setupCloneSource()
+	defer teardown()
+	var created DNSAppOpts
+	target, targetMux, targetTeardown := newCloneTarget(`[{"id":10,"name":"cdn-a"},{"id":20,"name":"cdn-b"}]`, `[{"id":555,"name":"foo"}]`, &created)
+	defer targetTeardown()
+	targetMux.HandleFunc("/v2/config/applications/dns.json/555", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Unexpected %s request", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":555,"name":"foo","type":"RT_HTTP_PERFORMANCE","protocol":"dns","fallbackCname":"fallback.foo.com",
+			"availabilityThreshold":80,"version":3,"customField":"kept","platforms":[{"id":10,"cname":"a.foo.com","weight":3},{"id":20,"cname":"b.foo.com"}]}`)
+	})
+	published := false
+	targetMux.HandleFunc("/v2/config/applications/dns.json/555/publish", func(w http.ResponseWriter, r *http.Request) {
+		published = true
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":555,"name":"foo","version":3}`)
+	})
+	result, err := CloneDNSApp(client, 123, target, CloneOptions{UpdateExisting: true, Publish: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !published {
+		t.Error("Expected the existing application to be published")
+	}
+	if err := testValues("id", 555, result.App.Id); err != nil {
+		t.Error(err)
+	}
+}
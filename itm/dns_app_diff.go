@@ -0,0 +1,199 @@
+package itm
+
+import (
+	"fmt"
+	"strings"
+)
+
+const unifiedDiffContext = 3
+
+// DNSAppDiff describes how two sets of Openmix Application settings differ.
+// Changes to appData are reported as a unified line diff rather than in
+// Fields.
+type DNSAppDiff struct {
+	Fields  []FieldDiff
+	AppData string
+}
+
+// IsEmpty reports whether both sides are identical
+func (d *DNSAppDiff) IsEmpty() bool {
+	return len(d.Fields) == 0 && d.AppData == ""
+}
+
+func (d *DNSAppDiff) String() string {
+	var result strings.Builder
+	for _, current := range d.Fields {
+		result.WriteString(current.String())
+		result.WriteString("\n")
+	}
+	result.WriteString(d.AppData)
+	return result.String()
+}
+
+// DiffDNSAppVersions compares two versions of an Openmix Application, as
+// returned by DNSApps.GetVersion or DNSApps.Versions
+func DiffDNSAppVersions(from *DNSApp, to *DNSApp) (*DNSAppDiff, error) {
+	fromOpts := from.ToOpts()
+	toOpts := to.ToOpts()
+	return diffDNSAppOpts(&fromOpts, &toOpts, fmt.Sprintf("version %d", from.Version), fmt.Sprintf("version %d", to.Version))
+}
+
+// DiffDNSAppOpts compares a live Openmix Application with local settings,
+// e.g. loaded from files before an upload
+func DiffDNSAppOpts(app *DNSApp, opts *DNSAppOpts) (*DNSAppDiff, error) {
+	live := app.ToOpts()
+	return diffDNSAppOpts(&live, opts, fmt.Sprintf("version %d", app.Version), "local")
+}
+
+func diffDNSAppOpts(from *DNSAppOpts, to *DNSAppOpts, fromName string, toName string) (*DNSAppDiff, error) {
+	fromData := strings.TrimSpace(from.AppData)
+	toData := strings.TrimSpace(to.AppData)
+	fromCopy := *from
+	toCopy := *to
+	fromCopy.AppData = ""
+	toCopy.AppData = ""
+	fields, err := diffOpts(&fromCopy, &toCopy)
+	if err != nil {
+		return nil, err
+	}
+	return &DNSAppDiff{
+		Fields:  fields,
+		AppData: unifiedDiff(fromName, toName, fromData, toData),
+	}, nil
+}
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff returns a unified diff of two texts, or an empty string when
+// they are identical
+func unifiedDiff(fromName string, toName string, from string, to string) string {
+	if from == to {
+		return ""
+	}
+	lines := diffLines(splitLines(from), splitLines(to))
+	var result strings.Builder
+	fmt.Fprintf(&result, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(lines); {
+		// Find the next change, then extend the hunk while changes are
+		// close enough for their context lines to overlap
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		last := first
+		for index := first; index < len(lines); index++ {
+			if lines[index].op != ' ' {
+				last = index
+			} else if index-last > 2*unifiedDiffContext {
+				break
+			}
+		}
+		hunkStart := maxInt(first-unifiedDiffContext, start)
+		hunkEnd := minInt(last+unifiedDiffContext+1, len(lines))
+		writeHunk(&result, lines, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+	return result.String()
+}
+
+func writeHunk(result *strings.Builder, lines []diffLine, start int, end int) {
+	// Line numbers are 1-based and count the lines before the hunk
+	fromLine, toLine := 1, 1
+	for _, current := range lines[:start] {
+		if current.op != '+' {
+			fromLine++
+		}
+		if current.op != '-' {
+			toLine++
+		}
+	}
+	fromCount, toCount := 0, 0
+	for _, current := range lines[start:end] {
+		if current.op != '+' {
+			fromCount++
+		}
+		if current.op != '-' {
+			toCount++
+		}
+	}
+	if fromCount == 0 {
+		fromLine--
+	}
+	if toCount == 0 {
+		toLine--
+	}
+	fmt.Fprintf(result, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+	for _, current := range lines[start:end] {
+		result.WriteByte(current.op)
+		result.WriteString(current.text)
+		result.WriteByte('\n')
+	}
+}
+
+// diffLines computes a minimal line edit script using the longest common
+// subsequence of both texts
+func diffLines(from []string, to []string) []diffLine {
+	lengths := make([][]int, len(from)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = maxInt(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	var result []diffLine
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			result = append(result, diffLine{' ', from[i]})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			result = append(result, diffLine{'-', from[i]})
+			i++
+		default:
+			result = append(result, diffLine{'+', to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		result = append(result, diffLine{'-', from[i]})
+	}
+	for ; j < len(to); j++ {
+		result = append(result, diffLine{'+', to[j]})
+	}
+	return result
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
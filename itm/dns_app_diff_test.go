@@ -0,0 +1,107 @@
+package itm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDNSAppVersions(t *testing.T) {
+	from := DNSApp{
+		Id:            123,
+		Name:          "foo",
+		AppData:       "function init(config) {\n    config.requireProvider('foo');\n}\n\nfunction onRequest(request, response) {\n    response.respond('foo', 'foo.com');\n    response.setTTL(20);\n}",
+		FallbackCname: "fallback.foo.com",
+		Type:          AppTypeCustomJavaScript,
+		Protocol:      ProtocolDNS,
+		FallbackTtl:   20,
+		Version:       3,
+	}
+	to := from
+	to.AppData = "function init(config) {\n    config.requireProvider('bar');\n}\n\nfunction onRequest(request, response) {\n    response.respond('bar', 'bar.com');\n    response.setTTL(20);\n}"
+	to.FallbackCname = "fallback.bar.com"
+	to.FallbackTtl = 30
+	to.Version = 4
+	diff, err := DiffDNSAppVersions(&from, &to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFields := []FieldDiff{
+		{Field: "fallbackCname", Live: "fallback.foo.com", Desired: "fallback.bar.com"},
+		{Field: "ttl", Live: float64(20), Desired: float64(30)},
+	}
+	if !reflect.DeepEqual(expectedFields, diff.Fields) {
+		t.Error(unexpectedValueString("fields", expectedFields, diff.Fields))
+	}
+	expectedAppData := `--- version 3
++++ version 4
+@@ -1,8 +1,8 @@
+ function init(config) {
+-    config.requireProvider('foo');
++    config.requireProvider('bar');
+ }
+ 
+ function onRequest(request, response) {
+-    response.respond('foo', 'foo.com');
++    response.respond('bar', 'bar.com');
+     response.setTTL(20);
+ }
+`
+	if err := testValues("app data diff", expectedAppData, diff.AppData); err != nil {
+		t.Error(err)
+	}
+	if diff.IsEmpty() {
+		t.Error("Expected a non-empty diff")
+	}
+}
+
+func TestDiffDNSAppOpts(t *testing.T) {
+	app := DNSApp{
+		Id:            123,
+		Name:          "foo",
+		AppData:       "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl",
+		FallbackCname: "fallback.foo.com",
+		Type:          AppTypeCustomJavaScript,
+		Protocol:      ProtocolDNS,
+		Version:       7,
+	}
+	opts := app.ToOpts()
+	diff, err := DiffDNSAppOpts(&app, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.IsEmpty() {
+		t.Errorf("Expected an empty diff; got %s", diff)
+	}
+	opts.AppData = "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm"
+	opts.Description = "foo description"
+	diff, err = DiffDNSAppOpts(&app, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `description:  -> foo description
+--- version 7
++++ local
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,3 +10,4 @@
+ j
+ k
+ l
++m
+`
+	if err := testValues("diff", expected, diff.String()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnifiedDiffFromEmpty(t *testing.T) {
+	expected := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+foo\n+bar\n"
+	if err := testValues("diff", expected, unifiedDiff("a", "b", "", "foo\nbar")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,176 @@
+package itm
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GetByName returns the Openmix Application with the given name. A
+// NotFoundError or an AmbiguousError is returned when there is not exactly
+// one match.
+func (s *dnsAppsServiceImpl) GetByName(name string) (*DNSApp, error) {
+	apps, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	return uniqueDNSApp(apps, "name", name, dnsAppNameMatches)
+}
+
+// GetByCname returns the Openmix Application ITM assigned the given CNAME.
+// The comparison ignores case and a trailing dot.
+func (s *dnsAppsServiceImpl) GetByCname(cname string) (*DNSApp, error) {
+	apps, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	return uniqueDNSApp(apps, "cname", cname, dnsAppCnameMatches)
+}
+
+// ResolveId returns the ID of the Openmix Application designated by key,
+// which may be a numeric ID, a CNAME or a name, tried in that order. Numeric
+// IDs are returned as is, without checking that the application exists.
+func (s *dnsAppsServiceImpl) ResolveId(key string) (int, error) {
+	if id, err := strconv.Atoi(key); err == nil {
+		return id, nil
+	}
+	apps, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	app, err := resolveDNSApp(apps, key)
+	if err != nil {
+		return 0, err
+	}
+	return app.Id, nil
+}
+
+func dnsAppNameMatches(app *DNSApp, name string) bool {
+	return app.Name == name
+}
+
+func dnsAppCnameMatches(app *DNSApp, cname string) bool {
+	return normalizeCname(app.AppCname) == normalizeCname(cname)
+}
+
+func normalizeCname(cname string) string {
+	return strings.ToLower(strings.TrimSuffix(cname, "."))
+}
+
+func uniqueDNSApp(apps []DNSApp, field string, value string, matches func(*DNSApp, string) bool) (*DNSApp, error) {
+	var found []int
+	for i := range apps {
+		if matches(&apps[i], value) {
+			found = append(found, i)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, &NotFoundError{Resource: "Openmix application", Field: field, Value: value}
+	case 1:
+		result := apps[found[0]].copy()
+		return &result, nil
+	}
+	ids := make([]int, len(found))
+	for i, index := range found {
+		ids[i] = apps[index].Id
+	}
+	return nil, &AmbiguousError{Resource: "Openmix application", Field: field, Value: value, Ids: ids}
+}
+
+// copy returns a deep copy of the application, so that it can be handed out
+// from a cache
+func (a DNSApp) copy() DNSApp {
+	a.Platforms = copyPlatformRefs(a.Platforms)
+	a.Extra = copyExtra(a.Extra)
+	return a
+}
+
+// resolveDNSApp looks key up as a CNAME first, then as a name
+func resolveDNSApp(apps []DNSApp, key string) (*DNSApp, error) {
+	app, err := uniqueDNSApp(apps, "cname", key, dnsAppCnameMatches)
+	if _, notFound := err.(*NotFoundError); !notFound {
+		return app, err
+	}
+	return uniqueDNSApp(apps, "name", key, dnsAppNameMatches)
+}
+
+// DNSAppIndex keeps the list of Openmix Applications in memory so they can be
+// looked up by name or CNAME without a request to the API each time. The
+// index only changes when Refresh is called. It is safe for concurrent use.
+type DNSAppIndex struct {
+	client      *Client
+	mutex       sync.RWMutex
+	apps        []DNSApp
+	refreshedAt time.Time
+}
+
+// NewDNSAppIndex creates an index of the Openmix Applications of client and
+// loads it
+func NewDNSAppIndex(client *Client) (*DNSAppIndex, error) {
+	result := &DNSAppIndex{client: client}
+	if err := result.Refresh(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Refresh reloads the list of Openmix Applications. When the request fails,
+// including with an unexpected HTTP status, the index keeps its previous
+// content.
+func (i *DNSAppIndex) Refresh() error {
+	apps, err := i.client.DNSApps.List()
+	if err != nil {
+		return err
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.apps = apps
+	i.refreshedAt = time.Now()
+	return nil
+}
+
+// RefreshedAt returns when the index was last loaded
+func (i *DNSAppIndex) RefreshedAt() time.Time {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return i.refreshedAt
+}
+
+// Len returns the number of indexed applications
+func (i *DNSAppIndex) Len() int {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return len(i.apps)
+}
+
+// ByName works like DNSApps.GetByName, using the indexed applications. The
+// result is a copy that the caller may modify.
+func (i *DNSAppIndex) ByName(name string) (*DNSApp, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return uniqueDNSApp(i.apps, "name", name, dnsAppNameMatches)
+}
+
+// ByCname works like DNSApps.GetByCname, using the indexed applications. The
+// result is a copy that the caller may modify.
+func (i *DNSAppIndex) ByCname(cname string) (*DNSApp, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return uniqueDNSApp(i.apps, "cname", cname, dnsAppCnameMatches)
+}
+
+// ResolveId works like DNSApps.ResolveId, using the indexed applications
+func (i *DNSAppIndex) ResolveId(key string) (int, error) {
+	if id, err := strconv.Atoi(key); err == nil {
+		return id, nil
+	}
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	app, err := resolveDNSApp(i.apps, key)
+	if err != nil {
+		return 0, err
+	}
+	return app.Id, nil
+}
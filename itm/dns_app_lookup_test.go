@@ -0,0 +1,177 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var lookupTestApps = []DNSApp{
+	{Id: 1, Name: "foo", AppCname: "2-01-abcd-0001.cdx.cedexis.net"},
+	{Id: 2, Name: "bar", AppCname: "2-01-abcd-0002.cdx.cedexis.net"},
+	{Id: 3, Name: "bar", AppCname: "2-01-abcd-0003.cdx.cedexis.net"},
+	{Id: 4, Name: "2-01-abcd-0001.cdx.cedexis.net", AppCname: "2-01-abcd-0004.cdx.cedexis.net"},
+}
+
+func setupLookupTestApps(t *testing.T) (func(), *int) {
+	teardown := setup()
+	appsJSON, _ := json.Marshal(lookupTestApps)
+	dnsApps := handleDNSApps(t, string(appsJSON))
+	return teardown, &dnsApps.listCalls
+}
+
+func TestDNSAppGetByName(t *testing.T) {
+	teardown, _ := setupLookupTestApps(t)
+	defer teardown()
+	app, err := client.DNSApps.GetByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("id", 1, app.Id); err != nil {
+		t.Error(err)
+	}
+	_, err = client.DNSApps.GetByName("bar")
+	ambiguous, ok := err.(*AmbiguousError)
+	if !ok {
+		t.Fatalf("Expected *AmbiguousError, got %v", err)
+	}
+	if !reflect.DeepEqual([]int{2, 3}, ambiguous.Ids) {
+		t.Error(unexpectedValueString("ids", []int{2, 3}, ambiguous.Ids))
+	}
+	if err := testValues("error", `2 Openmix applications found with name "bar": 2, 3`, err.Error()); err != nil {
+		t.Error(err)
+	}
+	_, err = client.DNSApps.GetByName("baz")
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Fatalf("Expected *NotFoundError, got %v", err)
+	}
+	if err := testValues("error", `No Openmix application found with name "baz"`, err.Error()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDNSAppGetByCname(t *testing.T) {
+	teardown, _ := setupLookupTestApps(t)
+	defer teardown()
+	app, err := client.DNSApps.GetByCname("2-01-ABCD-0002.cdx.cedexis.net.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("id", 2, app.Id); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDNSAppResolveId(t *testing.T) {
+	teardown, calls := setupLookupTestApps(t)
+	defer teardown()
+	testData := []struct {
+		key      string
+		expected int
+	}{
+		{"42", 42},
+		{"foo", 1},
+		{"2-01-abcd-0003.cdx.cedexis.net", 3},
+		// CNAMEs take precedence over names
+		{"2-01-abcd-0001.cdx.cedexis.net", 1},
+	}
+	for _, current := range testData {
+		id, err := client.DNSApps.ResolveId(current.key)
+		if err != nil {
+			t.Errorf("%s: %v", current.key, err)
+			continue
+		}
+		if err := testValues("id of "+current.key, current.expected, id); err != nil {
+			t.Error(err)
+		}
+	}
+	if err := testValues("list calls", 3, *calls); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDNSAppIndex(t *testing.T) {
+	teardown, calls := setupLookupTestApps(t)
+	defer teardown()
+	index, err := NewDNSAppIndex(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("length", 4, index.Len()); err != nil {
+		t.Error(err)
+	}
+	app, err := index.ByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("id", 1, app.Id); err != nil {
+		t.Error(err)
+	}
+	app, err = index.ByCname("2-01-abcd-0004.cdx.cedexis.net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("id", 4, app.Id); err != nil {
+		t.Error(err)
+	}
+	if _, err := index.ResolveId("bar"); err == nil {
+		t.Error("Expected an error for an ambiguous name")
+	}
+	if err := testValues("list calls", 1, *calls); err != nil {
+		t.Error(err)
+	}
+	refreshedAt := index.RefreshedAt()
+	if err := index.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("list calls", 2, *calls); err != nil {
+		t.Error(err)
+	}
+	if index.RefreshedAt().Before(refreshedAt) {
+		t.Error("Expected the refresh time to move forward")
+	}
+}
+
+func TestDNSAppIndexRefreshErrorAndCopies(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	failing := false
+	mux.HandleFunc("/v2/config/applications/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[{"id":1,"name":"foo","cname":"2-01-abcd-0001.cdx.cedexis.net","platforms":[{"id":12,"cname":"foo.com"}],"owner":"ops"}]`)
+	})
+	index, err := NewDNSAppIndex(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := index.ByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Platforms[0].Cname = "changed.com"
+	app.Extra["owner"] = json.RawMessage(`"someone"`)
+	app, err = index.ByCname("2-01-abcd-0001.cdx.cedexis.net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("cname", "foo.com", app.Platforms[0].Cname); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("owner", `"ops"`, string(app.Extra["owner"])); err != nil {
+		t.Error(err)
+	}
+	failing = true
+	if _, ok := index.Refresh().(*UnexpectedHTTPStatusError); !ok {
+		t.Error("Expected *UnexpectedHTTPStatusError")
+	}
+	if err := testValues("length", 1, index.Len()); err != nil {
+		t.Error(err)
+	}
+}
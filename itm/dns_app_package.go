@@ -0,0 +1,209 @@
+package itm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DNSAppMetadataFile is the name of the JSON file holding the settings of
+	// an Openmix Application kept in a directory
+	DNSAppMetadataFile = "app.json"
+	// DNSAppScriptFile is the default name of the JavaScript file holding
+	// appData. The metadata file may name another one in its "script" field.
+	DNSAppScriptFile = "app.js"
+	// MaxAppDataSize is the largest appData accepted by LintAppData, in bytes
+	MaxAppDataSize = 64 * 1024
+)
+
+var (
+	initHandlerRegexp      = regexp.MustCompile(`\bfunction\s+init\s*\(|\binit\s*=\s*function\b`)
+	onRequestHandlerRegexp = regexp.MustCompile(`\bfunction\s+onRequest\s*\(|\bonRequest\s*=\s*function\b`)
+)
+
+// LoadDNSAppOpts loads the settings of an Openmix Application from dir. The
+// metadata file holds the same fields as DNSAppOpts, except for appData
+// which is read from the script file. Any other field is rejected, rather
+// than sent to the API, and the script file must be inside dir.
+func LoadDNSAppOpts(dir string) (*DNSAppOpts, error) {
+	metadata, err := ioutil.ReadFile(filepath.Join(dir, DNSAppMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	var result DNSAppOpts
+	if err := json.Unmarshal(metadata, &result); err != nil {
+		return nil, fmt.Errorf("Invalid %s in %s: %v", DNSAppMetadataFile, dir, err)
+	}
+	scriptFile := DNSAppScriptFile
+	if raw, ok := result.Extra["script"]; ok {
+		if err := json.Unmarshal(raw, &scriptFile); err != nil {
+			return nil, fmt.Errorf("Invalid script field in %s: %v", DNSAppMetadataFile, err)
+		}
+		delete(result.Extra, "script")
+	}
+	if len(result.Extra) > 0 {
+		var keys []string
+		for key := range result.Extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("Unknown fields in %s: %s", DNSAppMetadataFile, strings.Join(keys, ", "))
+	}
+	result.Extra = nil
+	if !scriptInDir(scriptFile) {
+		return nil, fmt.Errorf("Invalid script field in %s: %q is outside %s", DNSAppMetadataFile, scriptFile, dir)
+	}
+	script, err := ioutil.ReadFile(filepath.Join(dir, scriptFile))
+	if err != nil {
+		return nil, err
+	}
+	result.AppData = strings.TrimSpace(string(script))
+	return &result, nil
+}
+
+// scriptInDir reports whether a script path, relative to the package
+// directory, stays inside it
+func scriptInDir(scriptFile string) bool {
+	if filepath.IsAbs(scriptFile) || filepath.VolumeName(scriptFile) != "" {
+		return false
+	}
+	clean := filepath.Clean(scriptFile)
+	return clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
+
+// AppDataHash returns a hex encoded SHA-256 hash of appData. Leading and
+// trailing whitespace is ignored, as NewDNSAppOpts strips it. Only the script
+// is covered: use DNSAppOptsHash to also detect changes to the metadata.
+func AppDataHash(appData string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(appData)))
+	return hex.EncodeToString(sum[:])
+}
+
+// DNSAppOptsHash returns a hex encoded SHA-256 hash of every setting in opts,
+// the script as well as the metadata. As with AppDataHash, whitespace
+// surrounding appData is ignored.
+func DNSAppOptsHash(opts *DNSAppOpts) (string, error) {
+	normalized := *opts
+	normalized.AppData = strings.TrimSpace(opts.AppData)
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Matches reports whether the Openmix Application already has the settings
+// in opts, so that uploading them would change nothing. Fields the server
+// keeps that opts does not mention, such as extra platform settings, are
+// ignored.
+func (a *DNSApp) Matches(opts *DNSAppOpts) (bool, error) {
+	diffs, err := a.Diff(opts)
+	if err != nil {
+		return false, err
+	}
+	return len(diffs) == 0, nil
+}
+
+// LintIssue describes a problem found in appData by LintAppData. Line is 1
+// based, or 0 when the issue concerns the script as a whole.
+type LintIssue struct {
+	Line    int
+	Message string
+}
+
+func (i LintIssue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+// LintAppData runs basic local checks on Openmix JavaScript before upload:
+// size, balanced braces, brackets and parentheses, and the presence of the
+// init and onRequest handlers. It is no substitute for a JavaScript parser;
+// in particular, regular expression literals are not recognised.
+func LintAppData(appData string) []LintIssue {
+	var result []LintIssue
+	if len(appData) > MaxAppDataSize {
+		result = append(result, LintIssue{0, fmt.Sprintf("script is %d bytes, more than the %d allowed", len(appData), MaxAppDataSize)})
+	}
+	result = append(result, lintBrackets(appData)...)
+	if !initHandlerRegexp.MatchString(appData) {
+		result = append(result, LintIssue{0, "missing init handler"})
+	}
+	if !onRequestHandlerRegexp.MatchString(appData) {
+		result = append(result, LintIssue{0, "missing onRequest handler"})
+	}
+	return result
+}
+
+// lintBrackets checks that brackets are balanced, skipping string literals
+// and comments
+func lintBrackets(script string) []LintIssue {
+	type opening struct {
+		char byte
+		line int
+	}
+	closers := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	var result []LintIssue
+	var stack []opening
+	line := 1
+	for i := 0; i < len(script); i++ {
+		char := script[i]
+		switch {
+		case char == '\n':
+			line++
+		case char == '/' && i+1 < len(script) && script[i+1] == '/':
+			for i+1 < len(script) && script[i+1] != '\n' {
+				i++
+			}
+		case char == '/' && i+1 < len(script) && script[i+1] == '*':
+			start := line
+			i += 2
+			for i < len(script) && !(script[i] == '*' && i+1 < len(script) && script[i+1] == '/') {
+				if script[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(script) {
+				return append(result, LintIssue{start, "unterminated comment"})
+			}
+			i++
+		case char == '"' || char == '\'' || char == '`':
+			start := line
+			i++
+			for i < len(script) && script[i] != char {
+				if script[i] == '\\' {
+					i++
+				} else if script[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(script) {
+				return append(result, LintIssue{start, "unterminated string"})
+			}
+		case char == '(' || char == '[' || char == '{':
+			stack = append(stack, opening{char, line})
+		case char == ')' || char == ']' || char == '}':
+			if len(stack) == 0 || stack[len(stack)-1].char != closers[char] {
+				result = append(result, LintIssue{line, fmt.Sprintf("unexpected %q", char)})
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for _, current := range stack {
+		result = append(result, LintIssue{current.line, fmt.Sprintf("unclosed %q", current.char)})
+	}
+	return result
+}
@@ -0,0 +1,174 @@
+package itm
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadDNSAppOpts(t *testing.T) {
+	opts, err := LoadDNSAppOpts("testdata/dns_app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("name", "foo", opts.Name); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("type", AppTypeCustomJavaScript, opts.Type); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual([]PlatformRef{NewPlatformRef(12, "foo.com")}, opts.Platforms) {
+		t.Error(unexpectedValueString("platforms", []PlatformRef{NewPlatformRef(12, "foo.com")}, opts.Platforms))
+	}
+	if !strings.HasPrefix(opts.AppData, "/* Route everything") || !strings.HasSuffix(opts.AppData, "}") {
+		t.Errorf("Unexpected app data: %q", opts.AppData)
+	}
+	if opts.Extra != nil {
+		t.Errorf("Expected script field not to be kept in Extra; got %v", opts.Extra)
+	}
+	if issues := LintAppData(opts.AppData); len(issues) != 0 {
+		t.Errorf("Unexpected lint issues: %v", issues)
+	}
+}
+
+func TestLoadDNSAppOptsMissingDir(t *testing.T) {
+	opts, err := LoadDNSAppOpts("testdata/missing")
+	if opts != nil || err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
+
+// writeDNSAppPackage writes a package directory holding metadata and an
+// app.js script, and returns the directory
+func writeDNSAppPackage(t *testing.T, metadata string) string {
+	dir, err := ioutil.TempDir("", "dns_app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, DNSAppMetadataFile), []byte(metadata), 0600)
+	ioutil.WriteFile(filepath.Join(dir, DNSAppScriptFile), []byte("function init() {}"), 0600)
+	return dir
+}
+
+func TestLoadDNSAppOptsScriptOutsideDir(t *testing.T) {
+	for _, script := range []string{"../app.js", "lib/../../app.js", "/etc/passwd"} {
+		dir := writeDNSAppPackage(t, `{"name":"foo","script":"`+script+`"}`)
+		defer os.RemoveAll(dir)
+		opts, err := LoadDNSAppOpts(dir)
+		if opts != nil || err == nil || !strings.Contains(err.Error(), "is outside") {
+			t.Errorf("Expected %s to be rejected; got %v", script, err)
+		}
+	}
+}
+
+func TestLoadDNSAppOptsUnknownFields(t *testing.T) {
+	dir := writeDNSAppPackage(t, `{"name":"foo","scirpt":"other.js","owner":"ops"}`)
+	defer os.RemoveAll(dir)
+	_, err := LoadDNSAppOpts(dir)
+	if err == nil {
+		t.Fatal("Expected an error for unknown fields")
+	}
+	if err := testValues("error", "Unknown fields in app.json: owner, scirpt", err.Error()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDNSAppOptsHash(t *testing.T) {
+	opts, err := LoadDNSAppOpts("testdata/dns_app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := DNSAppOptsHash(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := *opts
+	changed.AppData += "\n"
+	if same, _ := DNSAppOptsHash(&changed); same != hash {
+		t.Error("Expected surrounding whitespace to be ignored")
+	}
+	changed.Description = "another description"
+	if other, _ := DNSAppOptsHash(&changed); other == hash {
+		t.Error("Expected a metadata change to change the hash")
+	}
+}
+
+func TestAppDataHashAndMatches(t *testing.T) {
+	if AppDataHash("foo\n") != AppDataHash("  foo") {
+		t.Error("Expected surrounding whitespace to be ignored")
+	}
+	if AppDataHash("foo") == AppDataHash("bar") {
+		t.Error("Expected different hashes for different scripts")
+	}
+	opts, err := LoadDNSAppOpts("testdata/dns_app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := DNSApp{
+		Id:            123,
+		Name:          opts.Name,
+		AppData:       opts.AppData + "\n",
+		Description:   opts.Description,
+		FallbackCname: opts.FallbackCname,
+		Platforms:     opts.Platforms,
+		Type:          opts.Type,
+		Protocol:      opts.Protocol,
+		Version:       3,
+	}
+	matches, err := app.Matches(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !matches {
+		t.Error("Expected live application to match local files")
+	}
+	// The server adds fields that the package does not mention
+	if err := json.Unmarshal([]byte(`[{"id":12,"cname":"foo.com","platformName":"foo"}]`), &app.Platforms); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := app.Matches(opts); !matches {
+		t.Error("Expected live application with server fields to match local files")
+	}
+	app.AppData = "function init() {}"
+	if matches, _ := app.Matches(opts); matches {
+		t.Error("Expected live application not to match local files")
+	}
+}
+
+func TestLintAppData(t *testing.T) {
+	testData := []struct {
+		script   string
+		expected []LintIssue
+	}{
+		{
+			"function init(config) {}\nfunction onRequest(request, response) { var s = '{'; }",
+			nil,
+		},
+		{
+			"var init = function (config) {};\nvar onRequest = function (request, response) {\n    if (a) {\n};",
+			[]LintIssue{{2, `unclosed '{'`}},
+		},
+		{
+			"function init(config) { ]\n// }\n}",
+			[]LintIssue{{1, `unexpected ']'`}, {0, "missing onRequest handler"}},
+		},
+		{
+			"function onRequest() { /* } ",
+			[]LintIssue{{1, "unterminated comment"}, {0, "missing init handler"}},
+		},
+		{
+			"function init() {}\nfunction onRequest() {}\n" + strings.Repeat(" ", MaxAppDataSize),
+			[]LintIssue{{0, "script is 65579 bytes, more than the 65536 allowed"}},
+		},
+	}
+	for _, current := range testData {
+		issues := LintAppData(current.script)
+		if !reflect.DeepEqual(current.expected, issues) {
+			t.Error(unexpectedValueString("lint issues", current.expected, issues))
+		}
+	}
+}
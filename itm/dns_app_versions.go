@@ -0,0 +1,71 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Versions lists every saved version of an Openmix Application, oldest first
+func (s *dnsAppsServiceImpl) Versions(id int) ([]DNSApp, error) {
+	resp, err := s.client.get(getDNSAppVersionsPath(id))
+	if err != nil {
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode}
+	}
+	var result []DNSApp
+	json.Unmarshal(resp.Body, &result)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// GetVersion gets the appData and settings of an Openmix Application as they
+// were in the given version
+func (s *dnsAppsServiceImpl) GetVersion(id int, version int) (*DNSApp, error) {
+	var result DNSApp
+	resp, err := s.client.get(getDNSAppVersionPath(id, version))
+	if err != nil {
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode}
+	}
+	json.Unmarshal(resp.Body, &result)
+	return &result, nil
+}
+
+// Rollback restores the versioned content of an Openmix Application from the
+// given version and publishes it: the fields of DNSAppOpts, appData included.
+// Other live settings, such as whether the application is enabled or its
+// fallback TTL, are kept as they are. This saves a new version; the history
+// is left untouched.
+func (s *dnsAppsServiceImpl) Rollback(id int, version int) (*DNSApp, error) {
+	previous, err := s.GetVersion(id, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.Modify(id, func(opts *DNSAppOpts) error {
+		restored := previous.ToOpts()
+		restored.Extra = opts.Extra
+		*opts = restored
+		return nil
+	}, true)
+}
+
+// Get the URL listing the versions of an Openmix Application
+func getDNSAppVersionsPath(id int) string {
+	return fmt.Sprintf("%s/%d/versions", dnsAppsBasePath, id)
+}
+
+// Get the URL of a single version of an Openmix Application
+func getDNSAppVersionPath(id int, version int) string {
+	return fmt.Sprintf("%s/%d", getDNSAppVersionsPath(id), version)
+}
@@ -0,0 +1,113 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDnsAppVersions(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/dns.json/123/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[{"id":123,"appData":"v2","version":2},{"id":123,"appData":"v1","version":1},{"id":123,"appData":"v3","version":3}]`)
+	})
+	versions, err := client.DNSApps.Versions(123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("version count", 3, len(versions)); err != nil {
+		t.Fatal(err)
+	}
+	for index, current := range versions {
+		if err := testValues("version", index+1, current.Version); err != nil {
+			t.Error(err)
+		}
+		if err := testValues("app data", fmt.Sprintf("v%d", index+1), current.AppData); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestErrorIssuingGetDNSAppVersion(t *testing.T) {
+	fakeClient := newFakeHTTPClient(
+		fakeRoundTripper{
+			resp: nil,
+			err: &someError{
+				errorString: "foo",
+			},
+		})
+	testClient, _ := NewClient(HTTPClient(fakeClient))
+	app, err := testClient.DNSApps.GetVersion(123, 4)
+	if app != nil {
+		t.Error("Expected nil result")
+	}
+	expectedError := `Get "https://itm.cloud.com:443/api/v2/config/applications/dns.json/123/versions/4": foo`
+	if expectedError != err.Error() {
+		t.Errorf("Unexpected error.\nExpected: %s.\nGot: %s", expectedError, err.Error())
+	}
+}
+
+func TestDnsAppRollback(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/dns.json/123/versions/1", func(w http.ResponseWriter, r *http.Request) {
+		responseBodyObj := DNSApp{
+			Id:            123,
+			Name:          "foo",
+			AppData:       "v1",
+			FallbackCname: "fallback.foo.com",
+			FallbackTtl:   20,
+			Type:          AppTypeCustomJavaScript,
+			Protocol:      ProtocolDNS,
+			Version:       1,
+			Enabled:       true,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(responseBodyObj)
+		fmt.Fprint(w, string(responseBody))
+	})
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"id":123,"name":"foo","appData":"v3","type":"V1_JS","protocol":"dns","ttl":60,"enabled":false,"version":3}`)
+			return
+		}
+		if err := testValues("method", "PUT", r.Method); err != nil {
+			t.Error(err)
+		}
+		if err := testValues("publish", "true", r.URL.Query().Get("publish")); err != nil {
+			t.Error(err)
+		}
+		var parsedBody DNSAppOpts
+		if err := json.NewDecoder(r.Body).Decode(&parsedBody); err != nil {
+			t.Fatalf("JSON decoding error: %v", err)
+		}
+		if err := testValues("app data", "v1", parsedBody.AppData); err != nil {
+			t.Error(err)
+		}
+		if _, ok := parsedBody.Extra["version"]; ok {
+			t.Error("Expected version not to be sent back")
+		}
+		if err := testValues("ttl", "60", string(parsedBody.Extra["ttl"])); err != nil {
+			t.Error(err)
+		}
+		if err := testValues("enabled", "false", string(parsedBody.Extra["enabled"])); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":123,"appData":"v1","version":4}`)
+	})
+	app, err := client.DNSApps.Rollback(123, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("version", 4, app.Version); err != nil {
+		t.Error(err)
+	}
+}
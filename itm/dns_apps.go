@@ -1,10 +1,10 @@
 package itm
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/url"
 	"strings"
 )
 
@@ -12,19 +12,41 @@ const dnsAppsBasePath = "v2/config/applications/dns.json"
 
 // DNSAppOpts specifies settings used to create a new Citrix ITM Openmix Application
 type DNSAppOpts struct {
-	Name          string                   `json:"name"`
-	AppData       string                   `json:"appData"`
-	Description   string                   `json:"description"`
-	FallbackCname string                   `json:"fallbackCname"`
-	Platforms     []map[string]interface{} `json:"platforms"`
-	Type          string                   `json:"type"`
-	Protocol      string                   `json:"protocol"`
-	AvlThreshold  int                      `json:"availabilityThreshold"`
+	Name          string        `json:"name"`
+	AppData       string        `json:"appData"`
+	Description   string        `json:"description"`
+	FallbackCname string        `json:"fallbackCname"`
+	Platforms     []PlatformRef `json:"platforms"`
+	Type          AppType       `json:"type"`
+	Protocol      Protocol      `json:"protocol"`
+	AvlThreshold  int           `json:"availabilityThreshold"`
+	// Extra holds settings the SDK does not model. They are sent as is.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *DNSAppOpts) UnmarshalJSON(data []byte) error {
+	type plain DNSAppOpts
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = DNSAppOpts(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v DNSAppOpts) MarshalJSON() ([]byte, error) {
+	type plain DNSAppOpts
+	return marshalWithExtra(plain(v), v.Extra)
 }
 
 // NewDNSAppOpts creates and returns a new DNSAppOpts struct. Any leading or
 // trailing whitespace in appData is stripped in the resulting object.
-func NewDNSAppOpts(name string, appData string, description string, fallback string, platforms []map[string]interface{}, omapptype string, protocol string, threshold int) DNSAppOpts {
+func NewDNSAppOpts(name string, appData string, description string, fallback string, platforms []PlatformRef, omapptype AppType, protocol Protocol, threshold int) DNSAppOpts {
 	result := DNSAppOpts{
 		Name:          name,
 		AppData:       strings.TrimSpace(appData),
@@ -39,21 +61,77 @@ func NewDNSAppOpts(name string, appData string, description string, fallback str
 	return result
 }
 
+// NewValidatedDNSAppOpts works like NewDNSAppOpts, but also validates the
+// resulting settings. The returned error is a *ValidationError listing every
+// invalid field.
+func NewValidatedDNSAppOpts(name string, appData string, description string, fallback string, platforms []PlatformRef, omapptype AppType, protocol Protocol, threshold int) (DNSAppOpts, error) {
+	result := NewDNSAppOpts(name, appData, description, fallback, platforms, omapptype, protocol, threshold)
+	return result, result.Validate()
+}
+
+// Validate checks the settings against the requirements of their
+// application type: built-in types need at least one platform, custom
+// JavaScript applications need appData, and the availability threshold is
+// a percentage.
+func (o *DNSAppOpts) Validate() error {
+	return newValidationError(openmixAppFieldErrors(o.Name, o.AppData, o.FallbackCname, o.Platforms, o.Type, o.Protocol, o.AvlThreshold))
+}
+
 // DNSApp species settings of an existing Citrix Openmix Application
 type DNSApp struct {
-	Id            int                      `json:"id"`
-	Name          string                   `json:"name"`
-	AppData       string                   `json:"appData"`
-	AppCname      string                   `json:"cname"`
-	Description   string                   `json:"description"`
-	Type          string                   `json:"type"`
-	Protocol      string                   `json:"protocol"`
-	FallbackCname string                   `json:"fallbackCname"`
-	FallbackTtl   int                      `json:"ttl"`
-	Platforms     []map[string]interface{} `json:"platforms"`
-	AvlThreshold  int                      `json:"availabilityThreshold"`
-	Version       int                      `json:"version"`
-	Enabled       bool                     `json:"enabled"`
+	Id            int           `json:"id"`
+	Name          string        `json:"name"`
+	AppData       string        `json:"appData"`
+	AppCname      string        `json:"cname"`
+	Description   string        `json:"description"`
+	Type          AppType       `json:"type"`
+	Protocol      Protocol      `json:"protocol"`
+	FallbackCname string        `json:"fallbackCname"`
+	FallbackTtl   int           `json:"ttl"`
+	Platforms     []PlatformRef `json:"platforms"`
+	AvlThreshold  int           `json:"availabilityThreshold"`
+	Version       int           `json:"version"`
+	Enabled       bool          `json:"enabled"`
+	// Extra holds fields returned by the API that the SDK does not model.
+	// ToOpts carries them over, so that an update keeps them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *DNSApp) UnmarshalJSON(data []byte) error {
+	type plain DNSApp
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = DNSApp(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v DNSApp) MarshalJSON() ([]byte, error) {
+	type plain DNSApp
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// ToOpts returns the settings of the Openmix Application as a DNSAppOpts
+// struct, suitable for passing to Update. Fields DNSAppOpts does not model
+// are carried over in Extra, except for those assigned by ITM.
+func (a *DNSApp) ToOpts() DNSAppOpts {
+	return DNSAppOpts{
+		Name:          a.Name,
+		AppData:       a.AppData,
+		Description:   a.Description,
+		FallbackCname: a.FallbackCname,
+		Platforms:     copyPlatformRefs(a.Platforms),
+		Type:          a.Type,
+		Protocol:      a.Protocol,
+		AvlThreshold:  a.AvlThreshold,
+		Extra:         optsExtra(a, DNSAppOpts{}, "id", "cname", "version"),
+	}
 }
 
 type dnsAppsListTestFunc func(*DNSApp) bool
@@ -64,6 +142,20 @@ type dnsAppsService interface {
 	Get(int) (*DNSApp, error)
 	Delete(int) error
 	List(opts ...dnsAppsListTestFunc) ([]DNSApp, error)
+	Modify(int, func(*DNSAppOpts) error, bool) (*DNSApp, error)
+	UpdateIfChanged(int, *DNSAppOpts, bool) (*DNSApp, bool, error)
+	SaveDraft(int, *DNSAppOpts) (*DNSApp, error)
+	Publish(int) (*DNSApp, error)
+	Unpublish(int) (*DNSApp, error)
+	Enable(int) (*DNSApp, error)
+	Disable(int) (*DNSApp, error)
+	Versions(int) ([]DNSApp, error)
+	GetVersion(int, int) (*DNSApp, error)
+	Rollback(int, int) (*DNSApp, error)
+	GetByName(string) (*DNSApp, error)
+	GetByCname(string) (*DNSApp, error)
+	ResolveId(string) (int, error)
+	Rollout(context.Context, int, RolloutOptions) (*RolloutResult, error)
 }
 
 type dnsAppsServiceImpl struct {
@@ -76,16 +168,7 @@ func (s *dnsAppsServiceImpl) Create(opts *DNSAppOpts, publish bool) (*DNSApp, er
 	if err != nil {
 		return nil, err
 	}
-	publishVal := "false"
-	if publish {
-		publishVal = "true"
-	}
-	qs := &url.Values{
-		"publish": []string{
-			publishVal,
-		},
-	}
-	resp, err := s.client.post(dnsAppsBasePath, jsonOpts, qs)
+	resp, err := s.client.post(dnsAppsBasePath, jsonOpts, publishQueryString(publish))
 	if err != nil {
 		log.Printf("Error issuing post request from DNSAppsServiceImpl.Create: %v", err)
 		return nil, err
@@ -108,16 +191,7 @@ func (s *dnsAppsServiceImpl) Update(id int, opts *DNSAppOpts, publish bool) (*DN
 	if err != nil {
 		return nil, err
 	}
-	publishVal := "false"
-	if publish {
-		publishVal = "true"
-	}
-	qs := &url.Values{
-		"publish": []string{
-			publishVal,
-		},
-	}
-	resp, err := s.client.put(getDNSAppPath(id), jsonOpts, qs)
+	resp, err := s.client.put(getDNSAppPath(id), jsonOpts, publishQueryString(publish))
 	if err != nil {
 		log.Printf("Error issuing put request from DNSAppsServiceImpl.Update: %v", err)
 		return nil, err
@@ -169,6 +243,13 @@ func (s *dnsAppsServiceImpl) List(tests ...dnsAppsListTestFunc) ([]DNSApp, error
 	if err != nil {
 		return nil, err
 	}
+	if 200 != resp.StatusCode {
+		log.Printf("UnexpectedHTTPStatusError details: %s", string(resp.Body))
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
 	var all []DNSApp
 	var result []DNSApp
 	json.Unmarshal(resp.Body, &all)
@@ -187,7 +268,109 @@ func (s *dnsAppsServiceImpl) List(tests ...dnsAppsListTestFunc) ([]DNSApp, error
 	return result, nil
 }
 
+// Modify fetches an Openmix Application, applies mutate to its settings and
+// writes the result back, provided the application version has not moved in
+// the meantime. The cycle is retried a few times before giving up with a
+// ConflictError. The check is best effort: the API has no conditional update,
+// so a write by another client between the check and the update is lost.
+func (s *dnsAppsServiceImpl) Modify(id int, mutate func(*DNSAppOpts) error, publish bool) (*DNSApp, error) {
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		current, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		opts := current.ToOpts()
+		if err := mutate(&opts); err != nil {
+			return nil, err
+		}
+		latest, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if latest.Version != current.Version {
+			log.Printf("Openmix Application %d moved from version %d to %d; retrying", id, current.Version, latest.Version)
+			continue
+		}
+		return s.Update(id, &opts, publish)
+	}
+	return nil, &ConflictError{
+		Resource: "Openmix Application",
+		Id:       id,
+		Attempts: maxModifyAttempts,
+	}
+}
+
+// UpdateIfChanged updates an Openmix Application only when opts differs from
+// the live application. The returned bool reports whether an update was sent.
+func (s *dnsAppsServiceImpl) UpdateIfChanged(id int, opts *DNSAppOpts, publish bool) (*DNSApp, bool, error) {
+	current, err := s.Get(id)
+	if err != nil {
+		return nil, false, err
+	}
+	diffs, err := current.Diff(opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(diffs) == 0 {
+		return current, false, nil
+	}
+	result, err := s.Update(id, opts, publish)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
+// SaveDraft saves new settings for an Openmix Application without publishing
+// them, so that they can be reviewed and published later with Publish
+func (s *dnsAppsServiceImpl) SaveDraft(id int, opts *DNSAppOpts) (*DNSApp, error) {
+	return s.Update(id, opts, false)
+}
+
+// Publish makes the latest saved version of an Openmix Application live
+func (s *dnsAppsServiceImpl) Publish(id int) (*DNSApp, error) {
+	return s.action(id, "publish")
+}
+
+// Unpublish takes an Openmix Application out of service, keeping its settings
+func (s *dnsAppsServiceImpl) Unpublish(id int) (*DNSApp, error) {
+	return s.action(id, "unpublish")
+}
+
+// Enable an Openmix Application without resending its settings
+func (s *dnsAppsServiceImpl) Enable(id int) (*DNSApp, error) {
+	return s.action(id, "enable")
+}
+
+// Disable an Openmix Application without resending its settings
+func (s *dnsAppsServiceImpl) Disable(id int) (*DNSApp, error) {
+	return s.action(id, "disable")
+}
+
+func (s *dnsAppsServiceImpl) action(id int, action string) (*DNSApp, error) {
+	resp, err := s.client.post(getDNSAppActionPath(id, action), nil, nil)
+	if err != nil {
+		log.Printf("Error issuing post request from DNSAppsServiceImpl.%s: %v", action, err)
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		log.Printf("UnexpectedHTTPStatusError details: %s", string(resp.Body))
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	var result DNSApp
+	json.Unmarshal(resp.Body, &result)
+	return &result, nil
+}
+
 // Get Openmix Application APIs URL
 func getDNSAppPath(id int) string {
 	return fmt.Sprintf("%s/%d", dnsAppsBasePath, id)
 }
+
+// Get the URL of an action on an Openmix Application, such as publish
+func getDNSAppActionPath(id int, action string) string {
+	return fmt.Sprintf("%s/%d/%s", dnsAppsBasePath, id, action)
+}
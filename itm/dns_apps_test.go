@@ -10,11 +10,7 @@ import (
 )
 
 func TestErrorIssuingPostOnCreateDNSApps(t *testing.T) {
-	var platformList []map[string]interface{}
-	platformInstance := make(map[string]interface{})
-	platformInstance["id"] = float64(0)
-	platformInstance["cname"] = "foo.com"
-	platformList = append(platformList, platformInstance)
+	platformList := []PlatformRef{NewPlatformRef(0, "foo.com")}
 	fakeClient := newFakeHTTPClient(
 		fakeRoundTripper{
 			resp: nil,
@@ -35,11 +31,7 @@ func TestErrorIssuingPostOnCreateDNSApps(t *testing.T) {
 }
 
 func TestErrorIssuingPutOnUpdateDNSApps(t *testing.T) {
-	var platformList []map[string]interface{}
-	platformInstance := make(map[string]interface{})
-	platformInstance["id"] = 0
-	platformInstance["cname"] = "foo.com"
-	platformList = append(platformList, platformInstance)
+	platformList := []PlatformRef{NewPlatformRef(0, "foo.com")}
 	fakeClient := newFakeHTTPClient(
 		fakeRoundTripper{
 			resp: nil,
@@ -79,19 +71,15 @@ func TestErrorIssuingGetDNSApps(t *testing.T) {
 }
 
 func TestNewDnsAppOpts(t *testing.T) {
-	var platformList []map[string]interface{}
-	platformInstance := make(map[string]interface{})
-	platformInstance["id"] = float64(0)
-	platformInstance["cname"] = "foo.com"
-	platformList = append(platformList, platformInstance)
+	platformList := []PlatformRef{NewPlatformRef(0, "foo.com")}
 	var testData = []struct {
 		name          string
 		appData       string
 		description   string
 		fallbackCname string
-		platform      []map[string]interface{}
-		omapptype     string
-		protocol      string
+		platform      []PlatformRef
+		omapptype     AppType
+		protocol      Protocol
 		threshold     int
 	}{
 		{
@@ -109,7 +97,7 @@ func TestNewDnsAppOpts(t *testing.T) {
 			"Foo app data With spaces",
 			"Foo Description",
 			"Foo fallback CNAME",
-			[]map[string]interface{}{},
+			[]PlatformRef{},
 			"V1_JS",
 			"dns",
 			80,
@@ -148,11 +136,8 @@ func TestNewDnsAppOpts(t *testing.T) {
 func TestDnsAppCreate(t *testing.T) {
 	teardown := setup()
 	defer teardown()
-	var platformList []map[string]interface{}
-	platformInstance := make(map[string]interface{})
-	platformInstance["id"] = float64(0)
-	platformInstance["cname"] = "foo.com"
-	platformList = append(platformList, platformInstance)
+	platformList := []PlatformRef{NewPlatformRef(0, "foo.com")}
+	platformInstance := map[string]interface{}{"id": float64(0), "cname": "foo.com"}
 	var platInterface []interface{}
 	mux.HandleFunc("/v2/config/applications/dns.json", func(w http.ResponseWriter, r *http.Request) {
 		var parsedBody map[string]interface{}
@@ -237,11 +222,8 @@ func TestDnsAppCreate(t *testing.T) {
 func TestDnsAppUpdate(t *testing.T) {
 	teardown := setup()
 	defer teardown()
-	var platformList []map[string]interface{}
-	platformInstance := make(map[string]interface{})
-	platformInstance["id"] = float64(0)
-	platformInstance["cname"] = "foo.com"
-	platformList = append(platformList, platformInstance)
+	platformList := []PlatformRef{NewPlatformRef(0, "foo.com")}
+	platformInstance := map[string]interface{}{"id": float64(0), "cname": "foo.com"}
 	var platInterface []interface{} // this is because json.NewDecoder.Decode is coverting []map[string]interface{} to []interface{} while json conversion og platformList.
 
 	OMApp := DNSApp{
@@ -326,11 +308,7 @@ func TestDnsAppUpdate(t *testing.T) {
 func TestDnsAppGet(t *testing.T) {
 	teardown := setup()
 	defer teardown()
-	var platformList []map[string]interface{}
-	platformInstance := make(map[string]interface{})
-	platformInstance["id"] = float64(0)
-	platformInstance["cname"] = "foo.com"
-	platformList = append(platformList, platformInstance)
+	platformList := []PlatformRef{NewPlatformRef(0, "foo.com")}
 	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
 
 		responseBodyObj := DNSApp{
@@ -406,14 +384,7 @@ func TestDnsAppList(t *testing.T) {
 	defer teardown()
 	var OMApps []DNSApp
 
-	var platformList1 []map[string]interface{}
-	platformInstance1 := make(map[string]interface{})
-	platformInstance1["id"] = float64(0)
-	platformInstance1["cname"] = "foo.com"
-	platformInstance2 := make(map[string]interface{})
-	platformInstance2["id"] = float64(1)
-	platformInstance2["cname"] = "bar.com"
-	platformList1 = append(platformList1, platformInstance1, platformInstance2)
+	platformList1 := []PlatformRef{NewPlatformRef(0, "foo.com"), NewPlatformRef(1, "bar.com")}
 
 	OMApp1 := DNSApp{
 		Id:            123,
@@ -428,11 +399,7 @@ func TestDnsAppList(t *testing.T) {
 		Version:       1,
 	}
 
-	var platformList2 []map[string]interface{}
-	platformInstance3 := make(map[string]interface{})
-	platformInstance3["id"] = float64(0)
-	platformInstance3["cname"] = "foo.com"
-	platformList2 = append(platformList2, platformInstance3)
+	platformList2 := []PlatformRef{NewPlatformRef(0, "foo.com")}
 
 	OMApp2 := DNSApp{
 		Id:            456,
@@ -466,3 +433,313 @@ func TestDnsAppList(t *testing.T) {
 		}
 	}
 }
+
+func TestDnsAppModify(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	platformList := []PlatformRef{NewPlatformRef(0, "foo.com")}
+	OMApp := DNSApp{
+		Id:            123,
+		Name:          "foo",
+		AppData:       "foo app data",
+		Description:   "foo description",
+		FallbackCname: "fallback.foo.com",
+		Platforms:     platformList,
+		Type:          "RT_HTTP_PERFORMANCE",
+		Protocol:      "dns",
+		AvlThreshold:  80,
+		Version:       1,
+	}
+	puts := 0
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			puts++
+			var parsedBody DNSAppOpts
+			if err := json.NewDecoder(r.Body).Decode(&parsedBody); err != nil {
+				t.Fatalf("JSON decoding error: %v", err)
+			}
+			if err := testValues("description", "bar description", parsedBody.Description); err != nil {
+				t.Error(err)
+			}
+			if err := testValues("name", "foo", parsedBody.Name); err != nil {
+				t.Error(err)
+			}
+			if err := testValues("publish", "true", r.URL.Query().Get("publish")); err != nil {
+				t.Error(err)
+			}
+			OMApp.Description = parsedBody.Description
+			OMApp.Version++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(OMApp)
+		fmt.Fprint(w, string(responseBody))
+	})
+	app, err := client.DNSApps.Modify(123, func(opts *DNSAppOpts) error {
+		opts.Description = "bar description"
+		return nil
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("PUT requests", 1, puts); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("version", 2, app.Version); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDnsAppModifyConflict(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	version := 0
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			t.Error("Unexpected PUT request")
+		}
+		// Every read sees a newer version, as if someone else keeps deploying
+		version++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(DNSApp{Id: 123, Name: "foo", Version: version})
+		fmt.Fprint(w, string(responseBody))
+	})
+	app, err := client.DNSApps.Modify(123, func(opts *DNSAppOpts) error {
+		opts.Description = "bar description"
+		return nil
+	}, false)
+	if app != nil {
+		t.Error("Expected nil result")
+	}
+	conflict, ok := err.(*ConflictError)
+	if !ok {
+		t.Fatalf("Expected *ConflictError; got %v", err)
+	}
+	if err := testValues("attempts", maxModifyAttempts, conflict.Attempts); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDnsAppModifyMutateError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			t.Error("Unexpected PUT request")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(DNSApp{Id: 123, Name: "foo", Version: 1})
+		fmt.Fprint(w, string(responseBody))
+	})
+	_, err := client.DNSApps.Modify(123, func(opts *DNSAppOpts) error {
+		return &someError{errorString: "refused"}
+	}, false)
+	if err == nil || err.Error() != "refused" {
+		t.Errorf("Unexpected error.\nExpected: refused.\nGot: %v", err)
+	}
+}
+
+func TestDnsAppUpdateIfChanged(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	OMApp := DNSApp{
+		Id:            123,
+		Name:          "foo",
+		AppData:       "foo app data",
+		Description:   "foo description",
+		FallbackCname: "fallback.foo.com",
+		Platforms:     []PlatformRef{NewPlatformRef(0, "foo.com")},
+		Type:          "RT_HTTP_PERFORMANCE",
+		Protocol:      "dns",
+		AvlThreshold:  80,
+		Version:       1,
+	}
+	puts := 0
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			puts++
+			var parsedBody DNSAppOpts
+			if err := json.NewDecoder(r.Body).Decode(&parsedBody); err != nil {
+				t.Fatalf("JSON decoding error: %v", err)
+			}
+			OMApp.AvlThreshold = parsedBody.AvlThreshold
+			OMApp.Version++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(OMApp)
+		fmt.Fprint(w, string(responseBody))
+	})
+	opts := OMApp.ToOpts()
+	app, updated, err := client.DNSApps.UpdateIfChanged(123, &opts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated || puts != 0 {
+		t.Error("Expected no update to be sent for identical settings")
+	}
+	if err := testValues("version", 1, app.Version); err != nil {
+		t.Error(err)
+	}
+	opts.AvlThreshold = 90
+	app, updated, err = client.DNSApps.UpdateIfChanged(123, &opts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated || puts != 1 {
+		t.Error("Expected an update to be sent for changed settings")
+	}
+	if err := testValues("availability threshold", 90, app.AvlThreshold); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewValidatedDNSAppOpts(t *testing.T) {
+	platformList := []PlatformRef{NewPlatformRef(12, "foo.com")}
+	testData := []struct {
+		name      string
+		appData   string
+		fallback  string
+		platforms []PlatformRef
+		appType   AppType
+		protocol  Protocol
+		threshold int
+		expected  []FieldError
+	}{
+		{"foo", "", "fallback.foo.com", platformList, AppTypeOptimalRTT, ProtocolDNS, 80, nil},
+		{"foo", "function init() {}", "fallback.foo.com", nil, AppTypeCustomJavaScript, ProtocolDNS, 0, nil},
+		{
+			"foo", "", "fallback.foo.com", nil, AppTypeCustomJavaScript, ProtocolDNS, 0,
+			[]FieldError{{"appData", "is required for custom JavaScript applications"}},
+		},
+		{
+			"foo", "", "fallback.foo.com", nil, AppTypeOptimalRTT, ProtocolDNS, 80,
+			[]FieldError{{"platforms", "at least one platform is required for RT_HTTP_PERFORMANCE applications"}},
+		},
+		{
+			"", "", "", platformList, "RT_HTTP_PERF", "dsn", 101,
+			[]FieldError{
+				{"name", "is required"},
+				{"fallbackCname", "is required"},
+				{"type", `unknown application type "RT_HTTP_PERF"`},
+				{"protocol", `unknown protocol "dsn"`},
+				{"availabilityThreshold", "must be between 0 and 100"},
+			},
+		},
+	}
+	for _, current := range testData {
+		opts, err := NewValidatedDNSAppOpts(current.name, current.appData, "", current.fallback, current.platforms, current.appType, current.protocol, current.threshold)
+		if err := testValues("name", current.name, opts.Name); err != nil {
+			t.Error(err)
+		}
+		if current.expected == nil {
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			continue
+		}
+		validationErr, ok := err.(*ValidationError)
+		if !ok {
+			t.Errorf("Expected *ValidationError; got %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(current.expected, validationErr.Fields) {
+			t.Error(unexpectedValueString("field errors", current.expected, validationErr.Fields))
+		}
+	}
+}
+
+func TestDnsAppActions(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	OMApp := DNSApp{Id: 123, Name: "foo", Version: 2}
+	mux.HandleFunc("/v2/config/applications/dns.json/123/", func(w http.ResponseWriter, r *http.Request) {
+		if err := testValues("method", "POST", r.Method); err != nil {
+			t.Error(err)
+		}
+		switch r.URL.Path {
+		case "/v2/config/applications/dns.json/123/enable":
+			OMApp.Enabled = true
+		case "/v2/config/applications/dns.json/123/disable":
+			OMApp.Enabled = false
+		case "/v2/config/applications/dns.json/123/publish", "/v2/config/applications/dns.json/123/unpublish":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(OMApp)
+		fmt.Fprint(w, string(responseBody))
+	})
+	app, err := client.DNSApps.Enable(123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("enabled", true, app.Enabled); err != nil {
+		t.Error(err)
+	}
+	app, err = client.DNSApps.Disable(123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("enabled", false, app.Enabled); err != nil {
+		t.Error(err)
+	}
+	if _, err := client.DNSApps.Publish(123); err != nil {
+		t.Error(err)
+	}
+	if _, err := client.DNSApps.Unpublish(123); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDnsAppSaveDraftThenPublish(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	var calls []string
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" publish="+r.URL.Query().Get("publish"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":123,"version":3}`)
+	})
+	mux.HandleFunc("/v2/config/applications/dns.json/123/publish", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" /publish")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":123,"version":3}`)
+	})
+	opts := NewDNSAppOpts("foo", "foo app data", "", "fallback.foo.com", nil, AppTypeCustomJavaScript, ProtocolDNS, 0)
+	if _, err := client.DNSApps.SaveDraft(123, &opts); err != nil {
+		t.Fatal(err)
+	}
+	app, err := client.DNSApps.Publish(123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("version", 3, app.Version); err != nil {
+		t.Error(err)
+	}
+	expected := []string{"PUT publish=false", "POST /publish"}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Error(unexpectedValueString("calls", expected, calls))
+	}
+}
+
+func TestDnsAppPublishUnexpectedStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/dns.json/123/publish", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	app, err := client.DNSApps.Publish(123)
+	if app != nil {
+		t.Error("Expected nil result")
+	}
+	if _, ok := err.(*UnexpectedHTTPStatusError); !ok {
+		t.Errorf("Expected *UnexpectedHTTPStatusError; got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package itm
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const dnsQueriesPath = "v2/reporting/authdns/queries.json"
+
+// DNSQueryQuery selects the authoritative DNS queries to report on
+type DNSQueryQuery struct {
+	// ZoneIds and RecordTypes restrict the report; nothing is filtered out
+	// when they are empty
+	ZoneIds     []int
+	RecordTypes []string
+	Start       time.Time
+	End         time.Time
+	Interval    ReportInterval
+	// PageSize is the number of rows per page; a default applies when zero
+	PageSize int
+}
+
+// Validate checks the query. The returned error is a *ValidationError.
+func (q *DNSQueryQuery) Validate() error {
+	fields := timeRangeFieldErrors(q.Start, q.End)
+	if !q.Interval.IsValid() {
+		fields = append(fields, FieldError{"interval", "must be one of 5m, 1h, 1d"})
+	}
+	if q.PageSize < 0 {
+		fields = append(fields, FieldError{"pageSize", "must not be negative"})
+	}
+	return newValidationError(fields)
+}
+
+func (q *DNSQueryQuery) values(page int) url.Values {
+	qs := url.Values{}
+	setTimeRange(qs, q.Start, q.End)
+	qs.Set("interval", string(q.Interval))
+	setIds(qs, "zoneIds", q.ZoneIds)
+	if len(q.RecordTypes) > 0 {
+		qs.Set("recordTypes", strings.Join(q.RecordTypes, ","))
+	}
+	setPage(qs, page, q.PageSize)
+	return qs
+}
+
+// DNSQueryRow counts the queries answered for a name of a DNSZone, in a time
+// bucket, for a record type and a response code. Subdomain is empty for the
+// apex of the zone.
+type DNSQueryRow struct {
+	Time         time.Time `json:"timestamp"`
+	ZoneId       int       `json:"zoneId"`
+	Subdomain    string    `json:"subdomain"`
+	RecordType   string    `json:"recordType"`
+	ResponseCode string    `json:"responseCode"`
+	Count        int64     `json:"count"`
+}
+
+// DNSQueryPage is a page of a DNS query report
+type DNSQueryPage struct {
+	Page       int           `json:"page"`
+	TotalPages int           `json:"totalPages"`
+	Rows       []DNSQueryRow `json:"rows"`
+}
+
+type dnsQueryRows []DNSQueryRow
+
+func (r dnsQueryRows) Len() int {
+	return len(r)
+}
+
+func (r dnsQueryRows) At(i int) (time.Time, int64) {
+	return r[i].Time, r[i].Count
+}
+
+// DNSNameVolume is the number of queries answered for a name of a DNSZone
+type DNSNameVolume struct {
+	ZoneId    int
+	Subdomain string
+	Count     int64
+}
+
+// Name returns the fully qualified name, given the domain name of the zone
+func (v DNSNameVolume) Name(domainName string) string {
+	if v.Subdomain == "" || v.Subdomain == "@" {
+		return domainName
+	}
+	return v.Subdomain + "." + domainName
+}
+
+// DNSQueryReport holds every row of a DNS query report
+type DNSQueryReport struct {
+	Query DNSQueryQuery
+	Rows  []DNSQueryRow
+}
+
+// Total returns the number of queries in the report
+func (r *DNSQueryReport) Total() int64 {
+	return sumCounts(dnsQueryRows(r.Rows))
+}
+
+// Aggregate sums the queries by the key returned for each row
+func (r *DNSQueryReport) Aggregate(key func(*DNSQueryRow) string) map[string]int64 {
+	return sumCountsBy(dnsQueryRows(r.Rows), func(i int) string {
+		return key(&r.Rows[i])
+	})
+}
+
+// ByZone sums the queries by DNSZone ID
+func (r *DNSQueryReport) ByZone() map[int]int64 {
+	return sumCountsById(dnsQueryRows(r.Rows), func(i int) int {
+		return r.Rows[i].ZoneId
+	})
+}
+
+// ByRecordType sums the queries by record type
+func (r *DNSQueryReport) ByRecordType() map[string]int64 {
+	return r.Aggregate(func(row *DNSQueryRow) string {
+		return row.RecordType
+	})
+}
+
+// ByResponseCode sums the queries by response code
+func (r *DNSQueryReport) ByResponseCode() map[string]int64 {
+	return r.Aggregate(func(row *DNSQueryRow) string {
+		return row.ResponseCode
+	})
+}
+
+// ForZone returns the rows of a DNSZone
+func (r *DNSQueryReport) ForZone(zoneId int) []DNSQueryRow {
+	var result []DNSQueryRow
+	for _, current := range r.Rows {
+		if current.ZoneId == zoneId {
+			result = append(result, current)
+		}
+	}
+	return result
+}
+
+// Timeline sums the queries by time bucket, oldest first
+func (r *DNSQueryReport) Timeline() []ReportTotal {
+	return timeline(dnsQueryRows(r.Rows))
+}
+
+// TopNames returns the n names with the most queries, busiest first. Every
+// name is returned when n is not positive. The apex of a zone, reported with
+// an empty subdomain or "@", is returned with an empty subdomain.
+func (r *DNSQueryReport) TopNames(n int) []DNSNameVolume {
+	type name struct {
+		zoneId    int
+		subdomain string
+	}
+	totals := make(map[name]int64)
+	for _, current := range r.Rows {
+		subdomain := strings.ToLower(current.Subdomain)
+		if subdomain == "@" {
+			subdomain = ""
+		}
+		totals[name{current.ZoneId, subdomain}] += current.Count
+	}
+	var result []DNSNameVolume
+	for key, count := range totals {
+		result = append(result, DNSNameVolume{ZoneId: key.zoneId, Subdomain: key.subdomain, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].ZoneId != result[j].ZoneId {
+			return result[i].ZoneId < result[j].ZoneId
+		}
+		return result[i].Subdomain < result[j].Subdomain
+	})
+	if n > 0 && n < len(result) {
+		result = result[:n]
+	}
+	return result
+}
+
+type dnsReportingService interface {
+	QueriesPage(*DNSQueryQuery, int) (*DNSQueryPage, error)
+	Queries(*DNSQueryQuery) (*DNSQueryReport, error)
+}
+
+type dnsReportingServiceImpl struct {
+	client *Client
+}
+
+// QueriesPage returns one page of the queries selected by query. Pages are
+// numbered from 1.
+func (s *dnsReportingServiceImpl) QueriesPage(query *DNSQueryQuery, page int) (*DNSQueryPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	var result DNSQueryPage
+	if err := s.client.getReport(dnsQueriesPath, query.values(page), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Queries returns the queries selected by query, fetching every page
+func (s *dnsReportingServiceImpl) Queries(query *DNSQueryQuery) (*DNSQueryReport, error) {
+	result := &DNSQueryReport{Query: *query}
+	err := getPages(func(page int) (int, error) {
+		current, err := s.QueriesPage(query, page)
+		if err != nil {
+			return 0, err
+		}
+		result.Rows = append(result.Rows, current.Rows...)
+		return current.TotalPages, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
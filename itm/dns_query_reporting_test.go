@@ -0,0 +1,118 @@
+package itm
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var dnsQueryTestStart = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func dnsQueryTestQuery() *DNSQueryQuery {
+	return &DNSQueryQuery{
+		ZoneIds:     []int{7, 8},
+		RecordTypes: []string{"A", "AAAA"},
+		Start:       dnsQueryTestStart,
+		End:         dnsQueryTestStart.AddDate(0, 0, 1),
+		Interval:    IntervalHour,
+	}
+}
+
+func setupDNSQueries(t *testing.T) func() {
+	teardown := setup()
+	pages := []string{
+		`{"page":1,"totalPages":2,"rows":[
+			{"timestamp":"2026-10-01T00:00:00Z","zoneId":7,"subdomain":"www","recordType":"A","responseCode":"NOERROR","count":500},
+			{"timestamp":"2026-10-01T00:00:00Z","zoneId":7,"subdomain":"","recordType":"AAAA","responseCode":"NOERROR","count":200},
+			{"timestamp":"2026-10-01T00:00:00Z","zoneId":8,"subdomain":"api","recordType":"A","responseCode":"NOERROR","count":200}]}`,
+		`{"page":2,"totalPages":2,"rows":[
+			{"timestamp":"2026-10-01T01:00:00Z","zoneId":7,"subdomain":"WWW","recordType":"AAAA","responseCode":"NOERROR","count":100},
+			{"timestamp":"2026-10-01T01:00:00Z","zoneId":8,"subdomain":"old","recordType":"A","responseCode":"NXDOMAIN","count":100},
+			{"timestamp":"2026-10-01T01:00:00Z","zoneId":7,"subdomain":"@","recordType":"A","responseCode":"NOERROR","count":50}]}`,
+	}
+	handleReportPages(t, "/v2/reporting/authdns/queries.json", map[string]string{
+		"zoneIds":     "7,8",
+		"recordTypes": "A,AAAA",
+		"interval":    "1h",
+		"pageSize":    "1000",
+		"end":         fmt.Sprint(dnsQueryTestStart.AddDate(0, 0, 1).Unix()),
+	}, pages)
+	return teardown
+}
+
+func TestDNSQueries(t *testing.T) {
+	teardown := setupDNSQueries(t)
+	defer teardown()
+	report, err := client.DNSReporting.Queries(dnsQueryTestQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("rows", 6, len(report.Rows)); err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("total", int64(1150), report.Total()); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(map[int]int64{7: 850, 8: 300}, report.ByZone()) {
+		t.Error(unexpectedValueString("by zone", map[int]int64{7: 850, 8: 300}, report.ByZone()))
+	}
+	if !reflect.DeepEqual(map[string]int64{"A": 850, "AAAA": 300}, report.ByRecordType()) {
+		t.Error(unexpectedValueString("by record type", map[string]int64{"A": 850, "AAAA": 300}, report.ByRecordType()))
+	}
+	if !reflect.DeepEqual(map[string]int64{"NOERROR": 1050, "NXDOMAIN": 100}, report.ByResponseCode()) {
+		t.Error(unexpectedValueString("by response code", map[string]int64{"NOERROR": 1050, "NXDOMAIN": 100}, report.ByResponseCode()))
+	}
+	if err := testValues("zone rows", 2, len(report.ForZone(8))); err != nil {
+		t.Error(err)
+	}
+	expectedTimeline := []ReportTotal{
+		{Time: dnsQueryTestStart, Count: 900},
+		{Time: dnsQueryTestStart.Add(time.Hour), Count: 250},
+	}
+	if !reflect.DeepEqual(expectedTimeline, report.Timeline()) {
+		t.Error(unexpectedValueString("timeline", expectedTimeline, report.Timeline()))
+	}
+}
+
+func TestDNSQueriesTopNames(t *testing.T) {
+	teardown := setupDNSQueries(t)
+	defer teardown()
+	report, err := client.DNSReporting.Queries(dnsQueryTestQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DNSNameVolume{
+		{ZoneId: 7, Subdomain: "www", Count: 600},
+		{ZoneId: 7, Subdomain: "", Count: 250},
+		{ZoneId: 8, Subdomain: "api", Count: 200},
+	}
+	if !reflect.DeepEqual(expected, report.TopNames(3)) {
+		t.Error(unexpectedValueString("top names", expected, report.TopNames(3)))
+	}
+	if err := testValues("all names", 4, len(report.TopNames(0))); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("name", "www.example.com", expected[0].Name("example.com")); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("apex", "example.com", expected[1].Name("example.com")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDNSQueryQueryValidation(t *testing.T) {
+	query := &DNSQueryQuery{Start: dnsQueryTestStart, End: dnsQueryTestStart.Add(-time.Hour), Interval: IntervalDay, PageSize: -5}
+	_, err := client.DNSReporting.QueriesPage(query, 1)
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	expected := []FieldError{
+		{"end", "must be after start"},
+		{"pageSize", "must not be negative"},
+	}
+	if !reflect.DeepEqual(expected, validationErr.Fields) {
+		t.Error(unexpectedValueString("field errors", expected, validationErr.Fields))
+	}
+}
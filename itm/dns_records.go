@@ -16,6 +16,28 @@ type DNSRecordOpts struct {
 	OMAppId       string `json:"response"`
 	RecordType    string `json:"recordType"`
 	TTL           int    `json:"ttl"`
+	// Extra holds settings the SDK does not model. They are sent as is.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *DNSRecordOpts) UnmarshalJSON(data []byte) error {
+	type plain DNSRecordOpts
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = DNSRecordOpts(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v DNSRecordOpts) MarshalJSON() ([]byte, error) {
+	type plain DNSRecordOpts
+	return marshalWithExtra(plain(v), v.Extra)
 }
 
 // NewDNSRecordOpts creates and returns a new DNSRecord struct.
@@ -38,6 +60,43 @@ type DNSRecord struct {
 	OMAppId       string `json:"response"`
 	RecordType    string `json:"recordType"`
 	TTL           int    `json:"ttl"`
+	// Extra holds fields returned by the API that the SDK does not model.
+	// ToOpts carries them over, so that an update keeps them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *DNSRecord) UnmarshalJSON(data []byte) error {
+	type plain DNSRecord
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = DNSRecord(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v DNSRecord) MarshalJSON() ([]byte, error) {
+	type plain DNSRecord
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// ToOpts returns the settings of the DNS Record as a DNSRecordOpts struct,
+// suitable for passing to Update. Fields DNSRecordOpts does not model are
+// carried over in Extra.
+func (r *DNSRecord) ToOpts() DNSRecordOpts {
+	return DNSRecordOpts{
+		DNSZoneId:     r.DNSZoneId,
+		SubdomainName: r.SubdomainName,
+		OMAppId:       r.OMAppId,
+		RecordType:    r.RecordType,
+		TTL:           r.TTL,
+		Extra:         optsExtra(r, DNSRecordOpts{}, "id"),
+	}
 }
 
 type dnsRecordListTestFunc func(*DNSRecord) bool
@@ -47,6 +106,7 @@ type dnsRecordService interface {
 	Update(int, *DNSRecordOpts) (*DNSRecord, error)
 	Get(int) (*DNSRecord, error)
 	Delete(int) error
+	UpdateIfChanged(int, *DNSRecordOpts) (*DNSRecord, bool, error)
 }
 
 type dnsRecordServiceImpl struct {
@@ -126,6 +186,27 @@ func (s *dnsRecordServiceImpl) Delete(id int) error {
 	return err
 }
 
+// UpdateIfChanged updates a DNS Record only when opts differs from the live
+// record. The returned bool reports whether an update was sent.
+func (s *dnsRecordServiceImpl) UpdateIfChanged(id int, opts *DNSRecordOpts) (*DNSRecord, bool, error) {
+	current, err := s.Get(id)
+	if err != nil {
+		return nil, false, err
+	}
+	diffs, err := current.Diff(opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(diffs) == 0 {
+		return current, false, nil
+	}
+	result, err := s.Update(id, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
 // Get DNS Record APIs URL
 func getDNSRecordPath(id int) string {
 	return fmt.Sprintf("%s/%d", dnsRecordBasePath, id)
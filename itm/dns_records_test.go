@@ -271,3 +271,36 @@ func TestDNSRecordDelete(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestDNSRecordUpdateIfChanged(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/authdns.json/record/1234", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			t.Error("Unexpected PUT request")
+		}
+		responseBodyObj := DNSRecord{
+			Id:            1234,
+			DNSZoneId:     400,
+			SubdomainName: "sub.foo.domain.name",
+			OMAppId:       "{\"appId\":401}",
+			RecordType:    "CNAME",
+			TTL:           3333,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(responseBodyObj)
+		fmt.Fprint(w, string(responseBody))
+	})
+	opts := NewDNSRecordOpts(400, "sub.foo.domain.name", 401, "CNAME", 3333)
+	record, updated, err := client.DNSRecord.UpdateIfChanged(1234, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated {
+		t.Error("Expected no update to be sent for identical settings")
+	}
+	if err := testValues("id", 1234, record.Id); err != nil {
+		t.Error(err)
+	}
+}
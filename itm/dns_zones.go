@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"reflect"
 )
 
 const dnsZoneBasePath = "v2/config/authdns.json"
@@ -13,6 +14,28 @@ type DNSZoneOpts struct {
 	IsPrimary   bool   `json:"isPrimary"`
 	DomainName  string `json:"domainName"`
 	Description string `json:"description"`
+	// Extra holds settings the SDK does not model. They are sent as is.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *DNSZoneOpts) UnmarshalJSON(data []byte) error {
+	type plain DNSZoneOpts
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = DNSZoneOpts(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v DNSZoneOpts) MarshalJSON() ([]byte, error) {
+	type plain DNSZoneOpts
+	return marshalWithExtra(plain(v), v.Extra)
 }
 
 // NewDNSZoneOpts creates and returns a new DNSZone struct.
@@ -28,10 +51,45 @@ func NewDNSZoneOpts(name string, description string) DNSZoneOpts {
 // DNSZoneApp species settings of an existing Citrix ITM DNS Zone
 type DNSZone struct {
 	Id          int                      `json:"id"`
-	IsPrimary   bool                     `json:isPrimary`
+	IsPrimary   bool                     `json:"isPrimary"`
 	DomainName  string                   `json:"domainName"`
 	Description string                   `json:"description"`
 	Records     []map[string]interface{} `json:"records"`
+	// Extra holds fields returned by the API that the SDK does not model.
+	// ToOpts carries them over, so that an update keeps them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *DNSZone) UnmarshalJSON(data []byte) error {
+	type plain DNSZone
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = DNSZone(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v DNSZone) MarshalJSON() ([]byte, error) {
+	type plain DNSZone
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// ToOpts returns the settings of the DNS Zone as a DNSZoneOpts struct,
+// suitable for passing to Update. Fields DNSZoneOpts does not model are
+// carried over in Extra; records are managed through DNSRecord instead.
+func (z *DNSZone) ToOpts() DNSZoneOpts {
+	return DNSZoneOpts{
+		IsPrimary:   z.IsPrimary,
+		DomainName:  z.DomainName,
+		Description: z.Description,
+		Extra:       optsExtra(z, DNSZoneOpts{}, "id", "records"),
+	}
 }
 
 type dnsZoneListTestFunc func(*DNSZone) bool
@@ -42,6 +100,8 @@ type dnsZoneService interface {
 	Get(int) (*DNSZone, error)
 	Delete(int) error
 	List(opts ...dnsZoneListTestFunc) ([]DNSZone, error)
+	Modify(int, func(*DNSZoneOpts) error) (*DNSZone, error)
+	UpdateIfChanged(int, *DNSZoneOpts) (*DNSZone, bool, error)
 }
 
 type dnsZoneServiceImpl struct {
@@ -144,6 +204,59 @@ func (s *dnsZoneServiceImpl) List(tests ...dnsZoneListTestFunc) ([]DNSZone, erro
 	return result, nil
 }
 
+// Modify fetches a DNS Zone, applies mutate to its settings and writes the
+// result back, provided the zone has not changed on the server in the
+// meantime. The cycle is retried a few times before giving up with a
+// ConflictError. The check is best effort: the API has no conditional update,
+// so a write by another client between the check and the update is lost.
+func (s *dnsZoneServiceImpl) Modify(id int, mutate func(*DNSZoneOpts) error) (*DNSZone, error) {
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		current, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		opts := current.ToOpts()
+		if err := mutate(&opts); err != nil {
+			return nil, err
+		}
+		latest, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, latest) {
+			log.Printf("DNS Zone %d changed while being modified; retrying", id)
+			continue
+		}
+		return s.Update(id, &opts)
+	}
+	return nil, &ConflictError{
+		Resource: "DNS Zone",
+		Id:       id,
+		Attempts: maxModifyAttempts,
+	}
+}
+
+// UpdateIfChanged updates a DNS Zone only when opts differs from the live
+// zone. The returned bool reports whether an update was sent.
+func (s *dnsZoneServiceImpl) UpdateIfChanged(id int, opts *DNSZoneOpts) (*DNSZone, bool, error) {
+	current, err := s.Get(id)
+	if err != nil {
+		return nil, false, err
+	}
+	diffs, err := current.Diff(opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(diffs) == 0 {
+		return current, false, nil
+	}
+	result, err := s.Update(id, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
 // Get DNS Zone APIs URL
 func getDNSZonePath(id int) string {
 	return fmt.Sprintf("%s/%d", dnsZoneBasePath, id)
@@ -340,3 +340,38 @@ func TestDNSZoneList(t *testing.T) {
 		}
 	}
 }
+
+func TestDNSZoneModifyConflict(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	reads := 0
+	mux.HandleFunc("/v2/config/authdns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			t.Error("Unexpected PUT request")
+		}
+		reads++
+		responseBodyObj := DNSZone{
+			Id:          123,
+			IsPrimary:   true,
+			DomainName:  "foo.domain.name",
+			Description: fmt.Sprintf("description %d", reads),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(responseBodyObj)
+		fmt.Fprint(w, string(responseBody))
+	})
+	zone, err := client.DNSZone.Modify(123, func(opts *DNSZoneOpts) error {
+		opts.Description = "bar description"
+		return nil
+	})
+	if zone != nil {
+		t.Error("Expected nil result")
+	}
+	if _, ok := err.(*ConflictError); !ok {
+		t.Errorf("Expected *ConflictError; got %v", err)
+	}
+	if err := testValues("reads", 2*maxModifyAttempts, reads); err != nil {
+		t.Error(err)
+	}
+}
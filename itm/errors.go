@@ -1,5 +1,11 @@
 package itm
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // UnexpectedHTTPStatusError is an error type that outputs expected vs actual HTTP status
 type UnexpectedHTTPStatusError struct {
 	Expected int
@@ -9,3 +15,81 @@ type UnexpectedHTTPStatusError struct {
 func (e UnexpectedHTTPStatusError) Error() string {
 	return unexpectedValueString("HTTP status", e.Expected, e.Got)
 }
+
+// ConflictError is returned when a resource keeps changing on the server
+// between being read and being written back by a Modify call
+type ConflictError struct {
+	Resource string
+	Id       int
+	Attempts int
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("%s %d was modified concurrently; gave up after %d attempts", e.Resource, e.Id, e.Attempts)
+}
+
+// FieldError describes a single invalid setting
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError lists every invalid setting found while validating options
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e ValidationError) Error() string {
+	var messages []string
+	for _, current := range e.Fields {
+		messages = append(messages, current.Error())
+	}
+	return "Invalid settings: " + strings.Join(messages, "; ")
+}
+
+// newValidationError returns a *ValidationError for the given field errors,
+// or nil when there are none.
+func newValidationError(fields []FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// NotFoundError is returned when looking up a resource by something other
+// than its ID finds no match
+type NotFoundError struct {
+	Resource string
+	Field    string
+	Value    string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("No %s found with %s %q", e.Resource, e.Field, e.Value)
+}
+
+// AmbiguousError is returned when looking up a resource by something other
+// than its ID finds more than one match
+type AmbiguousError struct {
+	Resource string
+	Field    string
+	Value    string
+	Ids      []int
+}
+
+func (e AmbiguousError) Error() string {
+	return fmt.Sprintf("%d %ss found with %s %q: %s", len(e.Ids), e.Resource, e.Field, e.Value, joinIds(e.Ids))
+}
+
+// joinIds formats a list of IDs for an error message
+func joinIds(ids []int) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,119 @@
+package itm
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// unmarshalWithExtra decodes data into known, which must be a pointer to a
+// struct without a custom UnmarshalJSON method, and returns the top-level
+// fields that the struct does not declare.
+func unmarshalWithExtra(data []byte, known interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, known); err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	names := jsonFieldNames(reflect.TypeOf(known).Elem())
+	var extra map[string]json.RawMessage
+	for key, value := range all {
+		if names[strings.ToLower(key)] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[key] = value
+	}
+	return extra, nil
+}
+
+// marshalWithExtra encodes known, which must be a struct without a custom
+// MarshalJSON method, adding the fields in extra that it does not set itself.
+func marshalWithExtra(known interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(known)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if _, ok := all[key]; !ok {
+			all[key] = value
+		}
+	}
+	return json.Marshal(all)
+}
+
+// optsExtra returns the fields of resource that the opts struct does not
+// declare, leaving out the readOnly ones, so that settings unknown to the
+// SDK survive a Get followed by an Update.
+func optsExtra(resource interface{}, opts interface{}, readOnly ...string) map[string]json.RawMessage {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil
+	}
+	names := jsonFieldNames(reflect.TypeOf(opts))
+	for _, name := range readOnly {
+		names[strings.ToLower(name)] = true
+	}
+	var extra map[string]json.RawMessage
+	for key, value := range all {
+		if names[strings.ToLower(key)] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[key] = value
+	}
+	return extra
+}
+
+// jsonFieldNames returns the lower-cased JSON names of the fields declared
+// by a struct type. encoding/json matches names case-insensitively, so
+// lower-casing them gives the same notion of a known field.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	result := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		result[strings.ToLower(name)] = true
+	}
+	return result
+}
+
+func copyExtra(extra map[string]json.RawMessage) map[string]json.RawMessage {
+	if extra == nil {
+		return nil
+	}
+	result := make(map[string]json.RawMessage, len(extra))
+	for key, value := range extra {
+		result[key] = value
+	}
+	return result
+}
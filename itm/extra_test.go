@@ -0,0 +1,118 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func testLosslessRoundTrip(t *testing.T, label string, input string, value interface{}) {
+	if err := json.Unmarshal([]byte(input), value); err != nil {
+		t.Fatalf("%s: JSON decoding error: %v", label, err)
+	}
+	output, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("%s: JSON encoding error: %v", label, err)
+	}
+	var expected, got interface{}
+	json.Unmarshal([]byte(input), &expected)
+	json.Unmarshal(output, &got)
+	if !reflect.DeepEqual(expected, got) {
+		t.Error(unexpectedValueString(label+" round-trip", expected, got))
+	}
+}
+
+func TestLosslessRoundTrip(t *testing.T) {
+	testData := []struct {
+		label string
+		input string
+		value interface{}
+	}{
+		{
+			"DNSApp",
+			`{"id":123,"name":"foo","appData":"","cname":"foo.cname","description":"","type":"RT_HTTP_PERFORMANCE","protocol":"dns","fallbackCname":"fallback.foo.com","ttl":20,"platforms":[{"id":1,"cname":"foo.com"}],"availabilityThreshold":80,"version":3,"enabled":true,"marketId":7,"tags":["a","b"]}`,
+			&DNSApp{},
+		},
+		{
+			"DNSAppOpts",
+			`{"name":"foo","appData":"","description":"","fallbackCname":"fallback.foo.com","platforms":null,"type":"V1_JS","protocol":"dns","availabilityThreshold":0,"countryCode":"FR"}`,
+			&DNSAppOpts{},
+		},
+		{
+			"Platform",
+			`{"id":123,"name":"foo","displayName":"foo","category":{"id":1},"radarConfig":{"usePublicData":true,"httpEnabled":true,"httpsEnabled":false,"rttUrl":"http://foo.com/r20.gif","probeRegion":"eu"},"sonarConfig":{"enabled":false,"url":"https://foo.com/health","pollIntervalSeconds":60,"marketId":3},"intendedUse":"","enabled":true,"openmixEnabled":true,"privateArchetype":false,"openmixVisible":true,"publicProviderArchetypeId":5,"tags":[],"fusionConfig":{"provider":"bar"}}`,
+			&Platform{},
+		},
+		{
+			"DNSZone",
+			`{"id":123,"isPrimary":true,"domainName":"foo.domain.name","description":"","records":null,"tags":["x"]}`,
+			&DNSZone{},
+		},
+		{
+			"DNSRecord",
+			`{"id":1234,"dnsZoneId":123,"subdomainName":"xyz","response":"{\"appId\":401}","recordType":"CNAME","ttl":3600,"quickEdit":false}`,
+			&DNSRecord{},
+		},
+	}
+	for _, current := range testData {
+		testLosslessRoundTrip(t, current.label, current.input, current.value)
+	}
+}
+
+func TestUnknownFieldsKeptInExtra(t *testing.T) {
+	var app DNSApp
+	if err := json.Unmarshal([]byte(`{"id":123,"Name":"foo","marketId":7}`), &app); err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("name", "foo", app.Name); err != nil {
+		t.Error(err)
+	}
+	expected := map[string]json.RawMessage{"marketId": json.RawMessage("7")}
+	if !reflect.DeepEqual(expected, app.Extra) {
+		t.Error(unexpectedValueString("extra", expected, app.Extra))
+	}
+}
+
+func TestDnsAppGetUpdatePreservesUnknownFields(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	live := `{"id":123,"name":"foo","appData":"","cname":"foo.cname","description":"","type":"RT_HTTP_PERFORMANCE","protocol":"dns","fallbackCname":"fallback.foo.com","ttl":20,"platforms":[{"id":1,"cname":"foo.com"}],"availabilityThreshold":80,"version":3,"enabled":true,"marketId":7}`
+	mux.HandleFunc("/v2/config/applications/dns.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			var parsedBody map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&parsedBody); err != nil {
+				t.Fatalf("JSON decoding error: %v", err)
+			}
+			expectedRequestData := map[string]interface{}{
+				"name":                  "foo",
+				"appData":               "",
+				"description":           "bar description",
+				"fallbackCname":         "fallback.foo.com",
+				"platforms":             []interface{}{map[string]interface{}{"id": float64(1), "cname": "foo.com"}},
+				"type":                  "RT_HTTP_PERFORMANCE",
+				"protocol":              "dns",
+				"availabilityThreshold": float64(80),
+				"ttl":                   float64(20),
+				"enabled":               true,
+				"marketId":              float64(7),
+			}
+			if !reflect.DeepEqual(expectedRequestData, parsedBody) {
+				t.Error(unexpectedValueString("Request body", expectedRequestData, parsedBody))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, live)
+	})
+	app, err := client.DNSApps.Get(123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := app.ToOpts()
+	opts.Description = "bar description"
+	if _, err := client.DNSApps.Update(123, &opts, false); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,336 @@
+package itm
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DNSAppFilter selects Openmix Applications in DNSApps.List
+type DNSAppFilter = dnsAppsListTestFunc
+
+// HTTPAppFilter selects Openmix HTTP Applications in HTTPApps.List
+type HTTPAppFilter = httpAppsListTestFunc
+
+// PlatformFilter selects Platforms in Platform.List
+type PlatformFilter = platformListTestFunc
+
+// DNSZoneFilter selects DNS Zones in DNSZone.List
+type DNSZoneFilter = dnsZoneListTestFunc
+
+// globMatches reports whether name matches a pattern in the syntax of
+// path.Match, for instance "www-*". A malformed pattern matches nothing.
+func globMatches(pattern string, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// DNSAppNameMatches selects applications whose name matches the glob pattern,
+// using the syntax of path.Match. A malformed pattern matches nothing.
+func DNSAppNameMatches(pattern string) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		return globMatches(pattern, app.Name)
+	}
+}
+
+// DNSAppNameRegexp selects applications whose name matches re
+func DNSAppNameRegexp(re *regexp.Regexp) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		return re.MatchString(app.Name)
+	}
+}
+
+// DNSAppTypeIs selects applications of any of the given types
+func DNSAppTypeIs(types ...AppType) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		for _, current := range types {
+			if app.Type == current {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DNSAppProtocolIs selects applications answering the given protocol
+func DNSAppProtocolIs(protocol Protocol) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		return app.Protocol == protocol
+	}
+}
+
+// DNSAppEnabled selects enabled applications, or disabled ones when enabled
+// is false
+func DNSAppEnabled(enabled bool) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		return app.Enabled == enabled
+	}
+}
+
+// DNSAppUsesPlatform selects applications routing to the Platform with the
+// given ID, whether or not it is enabled in the application
+func DNSAppUsesPlatform(platformId int) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		return platformRefsContain(app.Platforms, platformId)
+	}
+}
+
+// DNSAppAnd selects applications selected by every filter
+func DNSAppAnd(filters ...DNSAppFilter) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		for _, current := range filters {
+			if !current(app) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DNSAppOr selects applications selected by at least one filter
+func DNSAppOr(filters ...DNSAppFilter) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		for _, current := range filters {
+			if current(app) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DNSAppNot selects applications not selected by filter
+func DNSAppNot(filter DNSAppFilter) DNSAppFilter {
+	return func(app *DNSApp) bool {
+		return !filter(app)
+	}
+}
+
+// HTTPAppNameMatches selects HTTP applications whose name matches the glob
+// pattern
+func HTTPAppNameMatches(pattern string) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		return globMatches(pattern, app.Name)
+	}
+}
+
+// HTTPAppNameRegexp selects HTTP applications whose name matches re
+func HTTPAppNameRegexp(re *regexp.Regexp) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		return re.MatchString(app.Name)
+	}
+}
+
+// HTTPAppTypeIs selects HTTP applications of any of the given types
+func HTTPAppTypeIs(types ...AppType) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		for _, current := range types {
+			if app.Type == current {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HTTPAppEnabled selects enabled HTTP applications, or disabled ones when
+// enabled is false
+func HTTPAppEnabled(enabled bool) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		return app.Enabled == enabled
+	}
+}
+
+// HTTPAppUsesPlatform selects HTTP applications routing to the Platform with
+// the given ID
+func HTTPAppUsesPlatform(platformId int) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		return platformRefsContain(app.Platforms, platformId)
+	}
+}
+
+// HTTPAppAnd selects HTTP applications selected by every filter
+func HTTPAppAnd(filters ...HTTPAppFilter) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		for _, current := range filters {
+			if !current(app) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// HTTPAppOr selects HTTP applications selected by at least one filter
+func HTTPAppOr(filters ...HTTPAppFilter) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		for _, current := range filters {
+			if current(app) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HTTPAppNot selects HTTP applications not selected by filter
+func HTTPAppNot(filter HTTPAppFilter) HTTPAppFilter {
+	return func(app *HTTPApp) bool {
+		return !filter(app)
+	}
+}
+
+// PlatformNameMatches selects platforms whose name matches the glob pattern
+func PlatformNameMatches(pattern string) PlatformFilter {
+	return func(platform *Platform) bool {
+		return globMatches(pattern, platform.Name)
+	}
+}
+
+// PlatformNameRegexp selects platforms whose name matches re
+func PlatformNameRegexp(re *regexp.Regexp) PlatformFilter {
+	return func(platform *Platform) bool {
+		return re.MatchString(platform.Name)
+	}
+}
+
+// PlatformEnabled selects enabled platforms, or disabled ones when enabled is
+// false
+func PlatformEnabled(enabled bool) PlatformFilter {
+	return func(platform *Platform) bool {
+		return platform.Enabled == enabled
+	}
+}
+
+// PlatformCategoryIs selects platforms of any of the given category IDs
+func PlatformCategoryIs(categoryIds ...int) PlatformFilter {
+	return func(platform *Platform) bool {
+		if platform.Category == nil {
+			return false
+		}
+		for _, current := range categoryIds {
+			if platform.Category.Id == current {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PlatformIdIn selects the platforms with the given IDs, for instance those
+// of an application
+func PlatformIdIn(ids ...int) PlatformFilter {
+	return func(platform *Platform) bool {
+		for _, current := range ids {
+			if platform.Id == current {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PlatformAnd selects platforms selected by every filter
+func PlatformAnd(filters ...PlatformFilter) PlatformFilter {
+	return func(platform *Platform) bool {
+		for _, current := range filters {
+			if !current(platform) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// PlatformOr selects platforms selected by at least one filter
+func PlatformOr(filters ...PlatformFilter) PlatformFilter {
+	return func(platform *Platform) bool {
+		for _, current := range filters {
+			if current(platform) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PlatformNot selects platforms not selected by filter
+func PlatformNot(filter PlatformFilter) PlatformFilter {
+	return func(platform *Platform) bool {
+		return !filter(platform)
+	}
+}
+
+// DNSZoneDomainMatches selects zones whose domain name matches the glob
+// pattern
+func DNSZoneDomainMatches(pattern string) DNSZoneFilter {
+	return func(zone *DNSZone) bool {
+		return globMatches(pattern, zone.DomainName)
+	}
+}
+
+// DNSZoneDomainRegexp selects zones whose domain name matches re
+func DNSZoneDomainRegexp(re *regexp.Regexp) DNSZoneFilter {
+	return func(zone *DNSZone) bool {
+		return re.MatchString(zone.DomainName)
+	}
+}
+
+// DNSZoneDomainSuffix selects zones for suffix or any of its subdomains.
+// Case and trailing dots are ignored, and only whole labels match:
+// "example.com" selects "www.example.com" but not "badexample.com".
+func DNSZoneDomainSuffix(suffix string) DNSZoneFilter {
+	suffix = normalizeCname(suffix)
+	return func(zone *DNSZone) bool {
+		domain := normalizeCname(zone.DomainName)
+		return domain == suffix || strings.HasSuffix(domain, "."+suffix)
+	}
+}
+
+// DNSZonePrimary selects primary zones, or secondary ones when primary is
+// false
+func DNSZonePrimary(primary bool) DNSZoneFilter {
+	return func(zone *DNSZone) bool {
+		return zone.IsPrimary == primary
+	}
+}
+
+// DNSZoneAnd selects zones selected by every filter
+func DNSZoneAnd(filters ...DNSZoneFilter) DNSZoneFilter {
+	return func(zone *DNSZone) bool {
+		for _, current := range filters {
+			if !current(zone) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DNSZoneOr selects zones selected by at least one filter
+func DNSZoneOr(filters ...DNSZoneFilter) DNSZoneFilter {
+	return func(zone *DNSZone) bool {
+		for _, current := range filters {
+			if current(zone) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DNSZoneNot selects zones not selected by filter
+func DNSZoneNot(filter DNSZoneFilter) DNSZoneFilter {
+	return func(zone *DNSZone) bool {
+		return !filter(zone)
+	}
+}
+
+func platformRefsContain(refs []PlatformRef, platformId int) bool {
+	for _, current := range refs {
+		if current.Id == platformId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func dnsAppIds(apps []DNSApp) []int {
+	var result []int
+	for _, current := range apps {
+		result = append(result, current.Id)
+	}
+	return result
+}
+
+func TestDNSAppFilters(t *testing.T) {
+	apps := []DNSApp{
+		{Id: 1, Name: "www-foo", Type: AppTypeOptimalRTT, Protocol: ProtocolDNS, Enabled: true, Platforms: []PlatformRef{NewPlatformRef(12, "foo.com")}},
+		{Id: 2, Name: "www-bar", Type: AppTypeRoundRobin, Protocol: ProtocolDNS, Enabled: false, Platforms: []PlatformRef{NewPlatformRef(34, "bar.com")}},
+		{Id: 3, Name: "api", Type: AppTypeCustomJavaScript, Protocol: ProtocolDNS, Enabled: true, Platforms: []PlatformRef{NewPlatformRef(12, "foo.com"), NewPlatformRef(34, "bar.com")}},
+	}
+	testData := []struct {
+		name     string
+		filter   DNSAppFilter
+		expected []int
+	}{
+		{"glob", DNSAppNameMatches("www-*"), []int{1, 2}},
+		{"malformed glob", DNSAppNameMatches("www-["), nil},
+		{"regexp", DNSAppNameRegexp(regexp.MustCompile("^(api|www-bar)$")), []int{2, 3}},
+		{"type", DNSAppTypeIs(AppTypeOptimalRTT, AppTypeCustomJavaScript), []int{1, 3}},
+		{"protocol", DNSAppProtocolIs(ProtocolHTTP), nil},
+		{"enabled", DNSAppEnabled(false), []int{2}},
+		{"platform", DNSAppUsesPlatform(34), []int{2, 3}},
+		{"and", DNSAppAnd(DNSAppEnabled(true), DNSAppUsesPlatform(12)), []int{1, 3}},
+		{"or", DNSAppOr(DNSAppNameMatches("api"), DNSAppTypeIs(AppTypeRoundRobin)), []int{2, 3}},
+		{"not", DNSAppNot(DNSAppNameMatches("www-*")), []int{3}},
+		{"empty and", DNSAppAnd(), []int{1, 2, 3}},
+	}
+	for _, current := range testData {
+		var got []int
+		for i := range apps {
+			if current.filter(&apps[i]) {
+				got = append(got, apps[i].Id)
+			}
+		}
+		if err := testValues(current.name, fmt.Sprint(current.expected), fmt.Sprint(got)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestDNSAppFiltersWithList(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[{"id":1,"name":"www-foo","enabled":true},{"id":2,"name":"www-bar"},{"id":3,"name":"api","enabled":true}]`)
+	})
+	apps, err := client.DNSApps.List(DNSAppNameMatches("www-*"), DNSAppEnabled(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("ids", "[1]", fmt.Sprint(dnsAppIds(apps))); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHTTPAppFilters(t *testing.T) {
+	apps := []HTTPApp{
+		{Id: 1, Name: "www-foo", Type: AppTypeOptimalRTT, Enabled: true, Platforms: []PlatformRef{NewPlatformRef(12, "foo.com")}},
+		{Id: 2, Name: "api", Type: AppTypeRoundRobin, Platforms: []PlatformRef{NewPlatformRef(34, "bar.com")}},
+	}
+	testData := []struct {
+		name     string
+		filter   HTTPAppFilter
+		expected []int
+	}{
+		{"glob", HTTPAppNameMatches("www-*"), []int{1}},
+		{"regexp", HTTPAppNameRegexp(regexp.MustCompile("^a")), []int{2}},
+		{"type", HTTPAppTypeIs(AppTypeRoundRobin), []int{2}},
+		{"enabled", HTTPAppEnabled(true), []int{1}},
+		{"platform", HTTPAppUsesPlatform(12), []int{1}},
+		{"and", HTTPAppAnd(HTTPAppEnabled(true), HTTPAppUsesPlatform(34)), nil},
+		{"or", HTTPAppOr(HTTPAppEnabled(true), HTTPAppUsesPlatform(34)), []int{1, 2}},
+		{"not", HTTPAppNot(HTTPAppEnabled(true)), []int{2}},
+	}
+	for _, current := range testData {
+		var got []int
+		for i := range apps {
+			if current.filter(&apps[i]) {
+				got = append(got, apps[i].Id)
+			}
+		}
+		if err := testValues(current.name, fmt.Sprint(current.expected), fmt.Sprint(got)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestPlatformFilters(t *testing.T) {
+	var platforms []Platform
+	json.Unmarshal([]byte(`[
+		{"id":12,"name":"cdn-foo","enabled":true,"category":{"id":1}},
+		{"id":34,"name":"cdn-bar","enabled":false,"category":{"id":2}},
+		{"id":56,"name":"origin","enabled":true}
+	]`), &platforms)
+	testData := []struct {
+		name     string
+		filter   PlatformFilter
+		expected []int
+	}{
+		{"glob", PlatformNameMatches("cdn-*"), []int{12, 34}},
+		{"regexp", PlatformNameRegexp(regexp.MustCompile("gin$")), []int{56}},
+		{"enabled", PlatformEnabled(true), []int{12, 56}},
+		{"category", PlatformCategoryIs(2, 3), []int{34}},
+		{"ids", PlatformIdIn(12, 56), []int{12, 56}},
+		{"and", PlatformAnd(PlatformNameMatches("cdn-*"), PlatformEnabled(true)), []int{12}},
+		{"or", PlatformOr(PlatformCategoryIs(1), PlatformNameMatches("origin")), []int{12, 56}},
+		{"not", PlatformNot(PlatformCategoryIs(1)), []int{34, 56}},
+	}
+	for _, current := range testData {
+		var got []int
+		for i := range platforms {
+			if current.filter(&platforms[i]) {
+				got = append(got, platforms[i].Id)
+			}
+		}
+		if err := testValues(current.name, fmt.Sprint(current.expected), fmt.Sprint(got)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestDNSZoneFilters(t *testing.T) {
+	zones := []DNSZone{
+		{Id: 1, DomainName: "example.com", IsPrimary: true},
+		{Id: 2, DomainName: "www.Example.com.", IsPrimary: true},
+		{Id: 3, DomainName: "badexample.com", IsPrimary: false},
+		{Id: 4, DomainName: "example.org", IsPrimary: true},
+	}
+	testData := []struct {
+		name     string
+		filter   DNSZoneFilter
+		expected []int
+	}{
+		{"suffix", DNSZoneDomainSuffix("example.com."), []int{1, 2}},
+		{"glob", DNSZoneDomainMatches("example.*"), []int{1, 4}},
+		{"regexp", DNSZoneDomainRegexp(regexp.MustCompile(`\.org$`)), []int{4}},
+		{"primary", DNSZonePrimary(false), []int{3}},
+		{"and", DNSZoneAnd(DNSZonePrimary(true), DNSZoneDomainMatches("*.com")), []int{1}},
+		{"or", DNSZoneOr(DNSZoneDomainSuffix("org"), DNSZonePrimary(false)), []int{3, 4}},
+		{"not", DNSZoneNot(DNSZoneDomainSuffix("com")), []int{4}},
+	}
+	for _, current := range testData {
+		var got []int
+		for i := range zones {
+			if current.filter(&zones[i]) {
+				got = append(got, zones[i].Id)
+			}
+		}
+		if err := testValues(current.name, fmt.Sprint(current.expected), fmt.Sprint(got)); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+const httpAppsBasePath = "v2/config/applications/http.json"
+
+// HTTPAppOpts specifies settings used to create a new Citrix ITM Openmix
+// Application answering HTTP requests with redirects
+type HTTPAppOpts struct {
+	Name          string        `json:"name"`
+	AppData       string        `json:"appData"`
+	Description   string        `json:"description"`
+	FallbackCname string        `json:"fallbackCname"`
+	Platforms     []PlatformRef `json:"platforms"`
+	Type          AppType       `json:"type"`
+	Protocol      Protocol      `json:"protocol"`
+	AvlThreshold  int           `json:"availabilityThreshold"`
+	// Extra holds settings the SDK does not model. They are sent as is.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *HTTPAppOpts) UnmarshalJSON(data []byte) error {
+	type plain HTTPAppOpts
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = HTTPAppOpts(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v HTTPAppOpts) MarshalJSON() ([]byte, error) {
+	type plain HTTPAppOpts
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// NewHTTPAppOpts creates and returns a new HTTPAppOpts struct. Any leading or
+// trailing whitespace in appData is stripped in the resulting object.
+func NewHTTPAppOpts(name string, appData string, description string, fallback string, platforms []PlatformRef, omapptype AppType, threshold int) HTTPAppOpts {
+	result := HTTPAppOpts{
+		Name:          name,
+		AppData:       strings.TrimSpace(appData),
+		Description:   description,
+		FallbackCname: fallback,
+		Platforms:     platforms,
+		Type:          omapptype,
+		Protocol:      ProtocolHTTP,
+		AvlThreshold:  threshold,
+	}
+
+	return result
+}
+
+// NewValidatedHTTPAppOpts works like NewHTTPAppOpts, but also validates the
+// resulting settings. The returned error is a *ValidationError listing every
+// invalid field.
+func NewValidatedHTTPAppOpts(name string, appData string, description string, fallback string, platforms []PlatformRef, omapptype AppType, threshold int) (HTTPAppOpts, error) {
+	result := NewHTTPAppOpts(name, appData, description, fallback, platforms, omapptype, threshold)
+	return result, result.Validate()
+}
+
+// Validate checks the settings the same way DNSAppOpts.Validate does. In
+// addition, the protocol must be ProtocolHTTP.
+func (o *HTTPAppOpts) Validate() error {
+	fields := openmixAppFieldErrors(o.Name, o.AppData, o.FallbackCname, o.Platforms, o.Type, o.Protocol, o.AvlThreshold)
+	if o.Protocol.IsValid() && o.Protocol != ProtocolHTTP {
+		fields = append(fields, FieldError{"protocol", fmt.Sprintf("must be %s for HTTP applications", ProtocolHTTP)})
+	}
+	return newValidationError(fields)
+}
+
+// HTTPApp species settings of an existing Citrix Openmix HTTP Application
+type HTTPApp struct {
+	Id            int           `json:"id"`
+	Name          string        `json:"name"`
+	AppData       string        `json:"appData"`
+	AppCname      string        `json:"cname"`
+	Description   string        `json:"description"`
+	Type          AppType       `json:"type"`
+	Protocol      Protocol      `json:"protocol"`
+	FallbackCname string        `json:"fallbackCname"`
+	Platforms     []PlatformRef `json:"platforms"`
+	AvlThreshold  int           `json:"availabilityThreshold"`
+	Version       int           `json:"version"`
+	Enabled       bool          `json:"enabled"`
+	// Extra holds fields returned by the API that the SDK does not model.
+	// ToOpts carries them over, so that an update keeps them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *HTTPApp) UnmarshalJSON(data []byte) error {
+	type plain HTTPApp
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = HTTPApp(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v HTTPApp) MarshalJSON() ([]byte, error) {
+	type plain HTTPApp
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// ToOpts returns the settings of the HTTP Application as an HTTPAppOpts
+// struct, suitable for passing to Update. Fields HTTPAppOpts does not model
+// are carried over in Extra, except for those assigned by ITM.
+func (a *HTTPApp) ToOpts() HTTPAppOpts {
+	return HTTPAppOpts{
+		Name:          a.Name,
+		AppData:       a.AppData,
+		Description:   a.Description,
+		FallbackCname: a.FallbackCname,
+		Platforms:     copyPlatformRefs(a.Platforms),
+		Type:          a.Type,
+		Protocol:      a.Protocol,
+		AvlThreshold:  a.AvlThreshold,
+		Extra:         optsExtra(a, HTTPAppOpts{}, "id", "cname", "version"),
+	}
+}
+
+type httpAppsListTestFunc func(*HTTPApp) bool
+
+type httpAppsService interface {
+	Create(*HTTPAppOpts, bool) (*HTTPApp, error)
+	Update(int, *HTTPAppOpts, bool) (*HTTPApp, error)
+	Get(int) (*HTTPApp, error)
+	Delete(int) error
+	List(opts ...httpAppsListTestFunc) ([]HTTPApp, error)
+	Modify(int, func(*HTTPAppOpts) error, bool) (*HTTPApp, error)
+	Publish(int) (*HTTPApp, error)
+	Unpublish(int) (*HTTPApp, error)
+}
+
+type httpAppsServiceImpl struct {
+	client *Client
+}
+
+// Create an Openmix HTTP Application
+func (s *httpAppsServiceImpl) Create(opts *HTTPAppOpts, publish bool) (*HTTPApp, error) {
+	jsonOpts, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.post(httpAppsBasePath, jsonOpts, publishQueryString(publish))
+	if err != nil {
+		log.Printf("Error issuing post request from HTTPAppsServiceImpl.Create: %v", err)
+		return nil, err
+	}
+	if 201 != resp.StatusCode {
+		log.Printf("UnexpectedHTTPStatusError details: %s", string(resp.Body))
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 201,
+			Got:      resp.StatusCode,
+		}
+	}
+	var result HTTPApp
+	json.Unmarshal(resp.Body, &result)
+	return &result, nil
+}
+
+// Update an Openmix HTTP Application
+func (s *httpAppsServiceImpl) Update(id int, opts *HTTPAppOpts, publish bool) (*HTTPApp, error) {
+	jsonOpts, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.put(getHTTPAppPath(id), jsonOpts, publishQueryString(publish))
+	if err != nil {
+		log.Printf("Error issuing put request from HTTPAppsServiceImpl.Update: %v", err)
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		log.Printf("UnexpectedHTTPStatusError details: %s", string(resp.Body))
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	var result HTTPApp
+	json.Unmarshal(resp.Body, &result)
+	return &result, nil
+}
+
+// Getting details of an Openmix HTTP Application using its ID
+func (s *httpAppsServiceImpl) Get(id int) (*HTTPApp, error) {
+	var result HTTPApp
+	resp, err := s.client.get(getHTTPAppPath(id))
+	if err != nil {
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode}
+	}
+	json.Unmarshal(resp.Body, &result)
+	return &result, nil
+}
+
+// Delete an Openmix HTTP Application using its ID
+func (s *httpAppsServiceImpl) Delete(id int) error {
+	resp, err := s.client.delete(getHTTPAppPath(id))
+	if err != nil {
+		return err
+	}
+	if 204 != resp.StatusCode {
+		return &UnexpectedHTTPStatusError{
+			Expected: 204,
+			Got:      resp.StatusCode,
+		}
+	}
+	return nil
+}
+
+// Get list of Openmix HTTP Applications
+func (s *httpAppsServiceImpl) List(tests ...httpAppsListTestFunc) ([]HTTPApp, error) {
+	resp, err := s.client.get(httpAppsBasePath)
+	if err != nil {
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		log.Printf("UnexpectedHTTPStatusError details: %s", string(resp.Body))
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	var all []HTTPApp
+	var result []HTTPApp
+	json.Unmarshal(resp.Body, &all)
+	for _, current := range all {
+		stillOk := true
+		for _, currentTest := range tests {
+			stillOk = currentTest(&current)
+			if !stillOk {
+				break
+			}
+		}
+		if stillOk {
+			result = append(result, current)
+		}
+	}
+	return result, nil
+}
+
+// Modify fetches an HTTP Application, applies mutate to its settings and
+// writes the result back, the same way DNSApps.Modify does.
+func (s *httpAppsServiceImpl) Modify(id int, mutate func(*HTTPAppOpts) error, publish bool) (*HTTPApp, error) {
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		current, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		opts := current.ToOpts()
+		if err := mutate(&opts); err != nil {
+			return nil, err
+		}
+		latest, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if latest.Version != current.Version {
+			log.Printf("HTTP Application %d moved from version %d to %d; retrying", id, current.Version, latest.Version)
+			continue
+		}
+		return s.Update(id, &opts, publish)
+	}
+	return nil, &ConflictError{
+		Resource: "HTTP Application",
+		Id:       id,
+		Attempts: maxModifyAttempts,
+	}
+}
+
+// Publish makes the latest saved version of an HTTP Application live
+func (s *httpAppsServiceImpl) Publish(id int) (*HTTPApp, error) {
+	return s.action(id, "publish")
+}
+
+// Unpublish takes an HTTP Application out of service, keeping its settings
+func (s *httpAppsServiceImpl) Unpublish(id int) (*HTTPApp, error) {
+	return s.action(id, "unpublish")
+}
+
+func (s *httpAppsServiceImpl) action(id int, action string) (*HTTPApp, error) {
+	resp, err := s.client.post(getHTTPAppActionPath(id, action), nil, nil)
+	if err != nil {
+		log.Printf("Error issuing post request from HTTPAppsServiceImpl.%s: %v", action, err)
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		log.Printf("UnexpectedHTTPStatusError details: %s", string(resp.Body))
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	var result HTTPApp
+	json.Unmarshal(resp.Body, &result)
+	return &result, nil
+}
+
+// Get Openmix HTTP Application APIs URL
+func getHTTPAppPath(id int) string {
+	return fmt.Sprintf("%s/%d", httpAppsBasePath, id)
+}
+
+// Get the URL of an action on an Openmix HTTP Application, such as publish
+func getHTTPAppActionPath(id int, action string) string {
+	return fmt.Sprintf("%s/%d/%s", httpAppsBasePath, id, action)
+}
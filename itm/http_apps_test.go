@@ -0,0 +1,253 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestErrorIssuingPostOnCreateHTTPApps(t *testing.T) {
+	fakeClient := newFakeHTTPClient(
+		fakeRoundTripper{
+			resp: nil,
+			err: &someError{
+				errorString: "foo",
+			},
+		})
+	testClient, _ := NewClient(HTTPClient(fakeClient))
+	createOps := NewHTTPAppOpts("foo", "", "foo description", "fallback.foo.com", []PlatformRef{NewPlatformRef(12, "foo.com")}, AppTypeOptimalRTT, 80)
+	app, err := testClient.HTTPApps.Create(&createOps, true)
+	if app != nil {
+		t.Error("Expected nil result")
+	}
+	expectedError := `Post "https://itm.cloud.com:443/api/v2/config/applications/http.json?publish=true": foo`
+	if expectedError != err.Error() {
+		t.Errorf("Unexpected error.\nExpected: %s.\nGot: %s", expectedError, err.Error())
+	}
+}
+
+func TestErrorIssuingGetHTTPApps(t *testing.T) {
+	fakeClient := newFakeHTTPClient(
+		fakeRoundTripper{
+			resp: nil,
+			err: &someError{
+				errorString: "foo",
+			},
+		})
+	testClient, _ := NewClient(HTTPClient(fakeClient))
+	app, err := testClient.HTTPApps.Get(123)
+	if app != nil {
+		t.Error("Expected nil result")
+	}
+	expectedError := `Get "https://itm.cloud.com:443/api/v2/config/applications/http.json/123": foo`
+	if expectedError != err.Error() {
+		t.Errorf("Unexpected error.\nExpected: %s.\nGot: %s", expectedError, err.Error())
+	}
+}
+
+func TestNewValidatedHTTPAppOpts(t *testing.T) {
+	opts, err := NewValidatedHTTPAppOpts("foo", "", "", "fallback.foo.com", []PlatformRef{NewPlatformRef(12, "foo.com")}, AppTypeOptimalRTT, 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("protocol", ProtocolHTTP, opts.Protocol); err != nil {
+		t.Error(err)
+	}
+	opts.Protocol = ProtocolDNS
+	validationErr, ok := opts.Validate().(*ValidationError)
+	if !ok {
+		t.Fatal("Expected *ValidationError")
+	}
+	expected := []FieldError{{"protocol", "must be http for HTTP applications"}}
+	if !reflect.DeepEqual(expected, validationErr.Fields) {
+		t.Error(unexpectedValueString("field errors", expected, validationErr.Fields))
+	}
+	if _, err := NewValidatedHTTPAppOpts("foo", "", "", "fallback.foo.com", nil, AppTypeRoundRobin, 0); err == nil {
+		t.Error("Expected an error for a built-in application without platforms")
+	}
+}
+
+func TestHTTPAppCreate(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/http.json", func(w http.ResponseWriter, r *http.Request) {
+		var parsedBody map[string]interface{}
+		expectedRequestData := map[string]interface{}{
+			"name":                  "foo",
+			"appData":               "",
+			"description":           "foo description",
+			"fallbackCname":         "fallback.foo.com",
+			"platforms":             []interface{}{map[string]interface{}{"id": float64(12), "cname": "foo.com"}},
+			"type":                  "RT_HTTP_PERFORMANCE",
+			"protocol":              "http",
+			"availabilityThreshold": float64(80),
+		}
+		err := json.NewDecoder(r.Body).Decode(&parsedBody)
+		if err != nil {
+			t.Fatalf("JSON decoding error: %v", err)
+		}
+		if !reflect.DeepEqual(expectedRequestData, parsedBody) {
+			t.Error(unexpectedValueString("Request body", expectedRequestData, parsedBody))
+		}
+		if err := testValues("publish", "false", r.URL.Query().Get("publish")); err != nil {
+			t.Error(err)
+		}
+		responseBodyObj := HTTPApp{
+			Id:            123,
+			Name:          "foo",
+			AppCname:      "foo app cname",
+			Description:   "foo description",
+			FallbackCname: "fallback.foo.com",
+			Platforms:     []PlatformRef{NewPlatformRef(12, "foo.com")},
+			Type:          AppTypeOptimalRTT,
+			Protocol:      ProtocolHTTP,
+			AvlThreshold:  80,
+			Version:       1,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		responseBody, _ := json.Marshal(responseBodyObj)
+		fmt.Fprint(w, string(responseBody))
+	})
+	createOps := NewHTTPAppOpts("foo", "", "foo description", "fallback.foo.com", []PlatformRef{NewPlatformRef(12, "foo.com")}, AppTypeOptimalRTT, 80)
+	app, err := client.HTTPApps.Create(&createOps, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("id", 123, app.Id); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("app CNAME", "foo app cname", app.AppCname); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("version", 1, app.Version); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHTTPAppUpdateAndPublish(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/http.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if err := testValues("method", "PUT", r.Method); err != nil {
+			t.Error(err)
+		}
+		var parsedBody HTTPAppOpts
+		if err := json.NewDecoder(r.Body).Decode(&parsedBody); err != nil {
+			t.Fatalf("JSON decoding error: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"id":123,"name":%q,"version":2}`, parsedBody.Name)
+	})
+	mux.HandleFunc("/v2/config/applications/http.json/123/publish", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":123,"name":"updated_foo","version":2,"enabled":true}`)
+	})
+	updateOpts := NewHTTPAppOpts("updated_foo", "", "", "fallback.foo.com", []PlatformRef{NewPlatformRef(12, "foo.com")}, AppTypeOptimalRTT, 80)
+	app, err := client.HTTPApps.Update(123, &updateOpts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("name", "updated_foo", app.Name); err != nil {
+		t.Error(err)
+	}
+	app, err = client.HTTPApps.Publish(123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("enabled", true, app.Enabled); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHTTPAppDelete(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/http.json/123", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if err := client.HTTPApps.Delete(123); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHTTPAppList(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	apps := []HTTPApp{
+		{Id: 123, Name: "foo", Type: AppTypeOptimalRTT, Protocol: ProtocolHTTP, Platforms: []PlatformRef{NewPlatformRef(12, "foo.com")}},
+		{Id: 456, Name: "bar", Type: AppTypeRoundRobin, Protocol: ProtocolHTTP, Platforms: []PlatformRef{NewPlatformRef(34, "bar.com")}},
+	}
+	mux.HandleFunc("/v2/config/applications/http.json/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(apps)
+		fmt.Fprint(w, string(responseBody))
+	})
+	all, err := client.HTTPApps.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(apps, all) {
+		t.Error(unexpectedValueString("HTTP apps", apps, all))
+	}
+	roundRobin, err := client.HTTPApps.List(func(app *HTTPApp) bool {
+		return app.Type == AppTypeRoundRobin
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roundRobin) != 1 || roundRobin[0].Id != 456 {
+		t.Error(unexpectedValueString("filtered HTTP apps", apps[1:], roundRobin))
+	}
+}
+
+func TestHTTPAppListUnexpectedStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/http.json/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	apps, err := client.HTTPApps.List()
+	statusErr, ok := err.(*UnexpectedHTTPStatusError)
+	if !ok {
+		t.Fatalf("Expected *UnexpectedHTTPStatusError, got %v", err)
+	}
+	if err := testValues("status", http.StatusUnauthorized, statusErr.Got); err != nil {
+		t.Error(err)
+	}
+	if apps != nil {
+		t.Error(unexpectedValueString("apps", nil, apps))
+	}
+}
+
+func TestHTTPAppModifyConflict(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	version := 0
+	mux.HandleFunc("/v2/config/applications/http.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			t.Error("Unexpected PUT request")
+		}
+		version++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(HTTPApp{Id: 123, Name: "foo", Version: version})
+		fmt.Fprint(w, string(responseBody))
+	})
+	_, err := client.HTTPApps.Modify(123, func(opts *HTTPAppOpts) error {
+		opts.Description = "bar description"
+		return nil
+	}, true)
+	conflict, ok := err.(*ConflictError)
+	if !ok {
+		t.Fatalf("Expected *ConflictError; got %v", err)
+	}
+	if err := testValues("resource", "HTTP Application", conflict.Resource); err != nil {
+		t.Error(err)
+	}
+}
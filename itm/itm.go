@@ -14,6 +14,7 @@ const (
 	libraryURL             = "https://github.com/cedexis/" + libraryName
 	defaultBaseURL         = "https://itm.cloud.com:443/api/"
 	defaultUserAgentString = libraryName + "/" + libraryVersion + " (" + libraryURL + ")"
+	maxModifyAttempts      = 3
 )
 
 var ClientToken string
@@ -25,10 +26,16 @@ type Client struct {
 	UserAgentString string
 
 	// Services
-	DNSApps   dnsAppsService
-	Platform  platformService
-	DNSZone   dnsZoneService
-	DNSRecord dnsRecordService
+	DNSApps          dnsAppsService
+	HTTPApps         httpAppsService
+	Platform         platformService
+	DNSZone          dnsZoneService
+	DNSRecord        dnsRecordService
+	Catalog          catalogService
+	Sonar            sonarService
+	Radar            radarService
+	OpenmixReporting openmixReportingService
+	DNSReporting     dnsReportingService
 }
 
 // ClientOpt is a generic type used to specify validated options for creating an ITM client
@@ -81,9 +88,15 @@ func NewClient(opts ...ClientOpt) (*Client, error) {
 		UserAgentString: defaultUserAgentString,
 	}
 	result.DNSApps = &dnsAppsServiceImpl{client: result}
+	result.HTTPApps = &httpAppsServiceImpl{client: result}
 	result.Platform = &platformServiceImpl{client: result}
 	result.DNSZone = &dnsZoneServiceImpl{client: result}
 	result.DNSRecord = &dnsRecordServiceImpl{client: result}
+	result.Catalog = &catalogServiceImpl{client: result}
+	result.Sonar = &sonarServiceImpl{client: result}
+	result.Radar = &radarServiceImpl{client: result}
+	result.OpenmixReporting = &openmixReportingServiceImpl{client: result}
+	result.DNSReporting = &dnsReportingServiceImpl{client: result}
 	if err := result.parseOptions(opts...); err != nil {
 		return nil, err
 	}
@@ -206,3 +219,17 @@ func (c *Client) delete(path string) (*response, error) {
 		Body:       nil,
 	}, nil
 }
+
+// publishQueryString returns the query string telling the API whether to
+// publish an Openmix Application being saved
+func publishQueryString(publish bool) *url.Values {
+	publishVal := "false"
+	if publish {
+		publishVal = "true"
+	}
+	return &url.Values{
+		"publish": []string{
+			publishVal,
+		},
+	}
+}
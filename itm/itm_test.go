@@ -3,6 +3,7 @@ package itm
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -50,6 +51,79 @@ func setup() func() {
 	}
 }
 
+// fakeDNSAppServer serves DNS Openmix Applications from memory, as the list
+// and one by one. The platforms of each PUT are applied to the stored
+// application and the update is recorded.
+type fakeDNSAppServer struct {
+	apps      []DNSApp
+	updates   []fakeDNSAppUpdate
+	listCalls int
+	// onUpdate, when set, is called with the application after each update
+	onUpdate func(app *DNSApp, r *http.Request)
+}
+
+type fakeDNSAppUpdate struct {
+	id      int
+	opts    DNSAppOpts
+	publish string
+}
+
+// handleDNSApps registers a fakeDNSAppServer holding the applications of
+// appsJSON on mux
+func handleDNSApps(t *testing.T, appsJSON string) *fakeDNSAppServer {
+	result := &fakeDNSAppServer{}
+	if err := json.Unmarshal([]byte(appsJSON), &result.apps); err != nil {
+		t.Fatal(err)
+	}
+	for i := range result.apps {
+		app := &result.apps[i]
+		mux.HandleFunc(fmt.Sprintf("/v2/config/applications/dns.json/%d", app.Id), func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "PUT" {
+				var opts DNSAppOpts
+				json.NewDecoder(r.Body).Decode(&opts)
+				app.Platforms = opts.Platforms
+				result.updates = append(result.updates, fakeDNSAppUpdate{app.Id, opts, r.URL.Query().Get("publish")})
+				if result.onUpdate != nil {
+					result.onUpdate(app, r)
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			body, _ := json.Marshal(app)
+			w.Write(body)
+		})
+	}
+	mux.HandleFunc("/v2/config/applications/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		result.listCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		body, _ := json.Marshal(result.apps)
+		w.Write(body)
+	})
+	return result
+}
+
+// app returns the stored application id
+func (s *fakeDNSAppServer) app(id int) *DNSApp {
+	for i := range s.apps {
+		if s.apps[i].Id == id {
+			return &s.apps[i]
+		}
+	}
+	return nil
+}
+
+// updatesOf returns the updates received for the application id
+func (s *fakeDNSAppServer) updatesOf(id int) []fakeDNSAppUpdate {
+	var result []fakeDNSAppUpdate
+	for _, current := range s.updates {
+		if current.id == id {
+			result = append(result, current)
+		}
+	}
+	return result
+}
+
 func testClientDefaults(t *testing.T, c *Client) {
 	if c.BaseURL.String() != defaultBaseURL {
 		t.Error(unexpectedValueString("Base URL", c.BaseURL, defaultBaseURL))
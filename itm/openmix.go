@@ -0,0 +1,106 @@
+package itm
+
+import "fmt"
+
+// AppType identifies the routing strategy of an Openmix Application
+type AppType string
+
+// Openmix Application types
+const (
+	// AppTypeCustomJavaScript runs the JavaScript given in appData
+	AppTypeCustomJavaScript AppType = "V1_JS"
+	// AppTypeOptimalRTT routes to the available platform with the lowest
+	// Radar round trip time
+	AppTypeOptimalRTT AppType = "RT_HTTP_PERFORMANCE"
+	// AppTypeThroughput routes to the available platform with the highest
+	// Radar throughput
+	AppTypeThroughput AppType = "KBPS_HTTP_PERFORMANCE"
+	// AppTypeRoundRobin spreads requests across the available platforms
+	AppTypeRoundRobin AppType = "ROUND_ROBIN"
+	// AppTypeStaticRouting always routes to the first available platform
+	AppTypeStaticRouting AppType = "STATIC_ROUTING"
+)
+
+// AppTypes lists every known Openmix Application type
+var AppTypes = []AppType{
+	AppTypeCustomJavaScript,
+	AppTypeOptimalRTT,
+	AppTypeThroughput,
+	AppTypeRoundRobin,
+	AppTypeStaticRouting,
+}
+
+// IsValid reports whether t is a known Openmix Application type
+func (t AppType) IsValid() bool {
+	for _, current := range AppTypes {
+		if t == current {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBuiltIn reports whether t is one of the ready-made ITM strategies, as
+// opposed to a custom JavaScript application
+func (t AppType) IsBuiltIn() bool {
+	return t.IsValid() && t != AppTypeCustomJavaScript
+}
+
+// UsesAvailabilityThreshold reports whether applications of type t drop
+// platforms whose Radar availability falls below the availability threshold
+func (t AppType) UsesAvailabilityThreshold() bool {
+	return t == AppTypeOptimalRTT || t == AppTypeThroughput
+}
+
+// Protocol identifies how an Openmix Application answers requests
+type Protocol string
+
+// Openmix Application protocols
+const (
+	ProtocolDNS  Protocol = "dns"
+	ProtocolHTTP Protocol = "http"
+)
+
+// Protocols lists every known Openmix Application protocol
+var Protocols = []Protocol{
+	ProtocolDNS,
+	ProtocolHTTP,
+}
+
+// IsValid reports whether p is a known Openmix Application protocol
+func (p Protocol) IsValid() bool {
+	for _, current := range Protocols {
+		if p == current {
+			return true
+		}
+	}
+	return false
+}
+
+// openmixAppFieldErrors checks the settings shared by every kind of Openmix
+// Application.
+func openmixAppFieldErrors(name string, appData string, fallback string, platforms []PlatformRef, appType AppType, protocol Protocol, threshold int) []FieldError {
+	var result []FieldError
+	if name == "" {
+		result = append(result, FieldError{"name", "is required"})
+	}
+	if fallback == "" {
+		result = append(result, FieldError{"fallbackCname", "is required"})
+	}
+	if !appType.IsValid() {
+		result = append(result, FieldError{"type", fmt.Sprintf("unknown application type %q", appType)})
+	}
+	if !protocol.IsValid() {
+		result = append(result, FieldError{"protocol", fmt.Sprintf("unknown protocol %q", protocol)})
+	}
+	if appType == AppTypeCustomJavaScript && appData == "" {
+		result = append(result, FieldError{"appData", "is required for custom JavaScript applications"})
+	}
+	if appType.IsBuiltIn() && len(platforms) == 0 {
+		result = append(result, FieldError{"platforms", fmt.Sprintf("at least one platform is required for %s applications", appType)})
+	}
+	if threshold < 0 || threshold > 100 {
+		result = append(result, FieldError{"availabilityThreshold", "must be between 0 and 100"})
+	}
+	return append(result, platformRefsFieldErrors(platforms)...)
+}
@@ -0,0 +1,192 @@
+package itm
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+const openmixDecisionsPath = "v2/reporting/openmix/decisions.json"
+
+// DecisionQuery selects the Openmix decisions to report on
+type DecisionQuery struct {
+	// AppIds, PlatformIds and Countries restrict the report; nothing is
+	// filtered out when they are empty
+	AppIds      []int
+	PlatformIds []int
+	Countries   []string
+	Start       time.Time
+	End         time.Time
+	Interval    ReportInterval
+	// PageSize is the number of rows per page; a default applies when zero
+	PageSize int
+}
+
+// Validate checks the query. The returned error is a *ValidationError.
+func (q *DecisionQuery) Validate() error {
+	fields := timeRangeFieldErrors(q.Start, q.End)
+	if !q.Interval.IsValid() {
+		fields = append(fields, FieldError{"interval", "must be one of 5m, 1h, 1d"})
+	}
+	if q.PageSize < 0 {
+		fields = append(fields, FieldError{"pageSize", "must not be negative"})
+	}
+	return newValidationError(fields)
+}
+
+func (q *DecisionQuery) values(page int) url.Values {
+	qs := url.Values{}
+	setTimeRange(qs, q.Start, q.End)
+	qs.Set("interval", string(q.Interval))
+	setIds(qs, "appIds", q.AppIds)
+	setIds(qs, "platformIds", q.PlatformIds)
+	if len(q.Countries) > 0 {
+		qs.Set("countries", strings.Join(q.Countries, ","))
+	}
+	setPage(qs, page, q.PageSize)
+	return qs
+}
+
+// DecisionRow counts the decisions an Openmix Application made for a
+// Platform, in a time bucket, for a country and a reason. PlatformId is zero
+// for decisions answered with the fallback CNAME.
+type DecisionRow struct {
+	Time       time.Time `json:"timestamp"`
+	AppId      int       `json:"appId"`
+	PlatformId int       `json:"platformId"`
+	Country    string    `json:"country"`
+	ReasonCode string    `json:"reasonCode"`
+	Count      int64     `json:"count"`
+}
+
+// DecisionPage is a page of an Openmix decision report
+type DecisionPage struct {
+	Page       int           `json:"page"`
+	TotalPages int           `json:"totalPages"`
+	Rows       []DecisionRow `json:"rows"`
+}
+
+type decisionRows []DecisionRow
+
+func (r decisionRows) Len() int {
+	return len(r)
+}
+
+func (r decisionRows) At(i int) (time.Time, int64) {
+	return r[i].Time, r[i].Count
+}
+
+// DecisionReport holds every row of an Openmix decision report
+type DecisionReport struct {
+	Query DecisionQuery
+	Rows  []DecisionRow
+}
+
+// Total returns the number of decisions in the report
+func (r *DecisionReport) Total() int64 {
+	return sumCounts(decisionRows(r.Rows))
+}
+
+// Aggregate sums the decisions by the key returned for each row
+func (r *DecisionReport) Aggregate(key func(*DecisionRow) string) map[string]int64 {
+	return sumCountsBy(decisionRows(r.Rows), func(i int) string {
+		return key(&r.Rows[i])
+	})
+}
+
+// ByPlatform sums the decisions by Platform ID
+func (r *DecisionReport) ByPlatform() map[int]int64 {
+	return sumCountsById(decisionRows(r.Rows), func(i int) int {
+		return r.Rows[i].PlatformId
+	})
+}
+
+// ByApp sums the decisions by Openmix Application ID
+func (r *DecisionReport) ByApp() map[int]int64 {
+	return sumCountsById(decisionRows(r.Rows), func(i int) int {
+		return r.Rows[i].AppId
+	})
+}
+
+// ByCountry sums the decisions by country
+func (r *DecisionReport) ByCountry() map[string]int64 {
+	return r.Aggregate(func(row *DecisionRow) string {
+		return row.Country
+	})
+}
+
+// ByReason sums the decisions by reason code
+func (r *DecisionReport) ByReason() map[string]int64 {
+	return r.Aggregate(func(row *DecisionRow) string {
+		return row.ReasonCode
+	})
+}
+
+// PlatformShares returns the percentage of the decisions that went to each
+// Platform
+func (r *DecisionReport) PlatformShares() map[int]float64 {
+	total := r.Total()
+	result := make(map[int]float64)
+	if total == 0 {
+		return result
+	}
+	for platformId, count := range r.ByPlatform() {
+		result[platformId] = 100 * float64(count) / float64(total)
+	}
+	return result
+}
+
+// Timeline sums the decisions by time bucket, oldest first
+func (r *DecisionReport) Timeline() []ReportTotal {
+	return timeline(decisionRows(r.Rows))
+}
+
+// Peak returns the busiest time bucket, for sizing capacity
+func (r *DecisionReport) Peak() ReportTotal {
+	var result ReportTotal
+	for _, current := range r.Timeline() {
+		if current.Count > result.Count {
+			result = current
+		}
+	}
+	return result
+}
+
+type openmixReportingService interface {
+	DecisionsPage(*DecisionQuery, int) (*DecisionPage, error)
+	Decisions(*DecisionQuery) (*DecisionReport, error)
+}
+
+type openmixReportingServiceImpl struct {
+	client *Client
+}
+
+// DecisionsPage returns one page of the decisions selected by query. Pages
+// are numbered from 1.
+func (s *openmixReportingServiceImpl) DecisionsPage(query *DecisionQuery, page int) (*DecisionPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	var result DecisionPage
+	if err := s.client.getReport(openmixDecisionsPath, query.values(page), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Decisions returns the decisions selected by query, fetching every page
+func (s *openmixReportingServiceImpl) Decisions(query *DecisionQuery) (*DecisionReport, error) {
+	result := &DecisionReport{Query: *query}
+	err := getPages(func(page int) (int, error) {
+		current, err := s.DecisionsPage(query, page)
+		if err != nil {
+			return 0, err
+		}
+		result.Rows = append(result.Rows, current.Rows...)
+		return current.TotalPages, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
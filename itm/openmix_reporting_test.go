@@ -0,0 +1,125 @@
+package itm
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var decisionTestStart = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func decisionTestQuery() *DecisionQuery {
+	return &DecisionQuery{
+		AppIds:    []int{123},
+		Countries: []string{"FR", "US"},
+		Start:     decisionTestStart,
+		End:       decisionTestStart.Add(2 * time.Hour),
+		Interval:  IntervalHour,
+		PageSize:  2,
+	}
+}
+
+func setupDecisions(t *testing.T) func() {
+	teardown := setup()
+	pages := []string{
+		`{"page":1,"totalPages":2,"rows":[
+			{"timestamp":"2026-10-01T00:00:00Z","appId":123,"platformId":12,"country":"FR","reasonCode":"A","count":600},
+			{"timestamp":"2026-10-01T00:00:00Z","appId":123,"platformId":34,"country":"US","reasonCode":"A","count":200}]}`,
+		`{"page":2,"totalPages":2,"rows":[
+			{"timestamp":"2026-10-01T01:00:00Z","appId":123,"platformId":12,"country":"US","reasonCode":"B","count":100},
+			{"timestamp":"2026-10-01T01:00:00Z","appId":123,"platformId":0,"country":"FR","reasonCode":"F","count":100}]}`,
+	}
+	handleReportPages(t, "/v2/reporting/openmix/decisions.json", map[string]string{
+		"appIds":    "123",
+		"countries": "FR,US",
+		"interval":  "1h",
+		"pageSize":  "2",
+		"start":     fmt.Sprint(decisionTestStart.Unix()),
+	}, pages)
+	return teardown
+}
+
+func TestDecisionsPage(t *testing.T) {
+	teardown := setupDecisions(t)
+	defer teardown()
+	page, err := client.OpenmixReporting.DecisionsPage(decisionTestQuery(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("page", 2, page.Page); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("rows", 2, len(page.Rows)); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("reason", "B", page.Rows[0].ReasonCode); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecisionsReport(t *testing.T) {
+	teardown := setupDecisions(t)
+	defer teardown()
+	report, err := client.OpenmixReporting.Decisions(decisionTestQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("rows", 4, len(report.Rows)); err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("total", int64(1000), report.Total()); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(map[int]int64{12: 700, 34: 200, 0: 100}, report.ByPlatform()) {
+		t.Error(unexpectedValueString("by platform", map[int]int64{12: 700, 34: 200, 0: 100}, report.ByPlatform()))
+	}
+	if !reflect.DeepEqual(map[string]int64{"FR": 700, "US": 300}, report.ByCountry()) {
+		t.Error(unexpectedValueString("by country", map[string]int64{"FR": 700, "US": 300}, report.ByCountry()))
+	}
+	if !reflect.DeepEqual(map[string]int64{"A": 800, "B": 100, "F": 100}, report.ByReason()) {
+		t.Error(unexpectedValueString("by reason", map[string]int64{"A": 800, "B": 100, "F": 100}, report.ByReason()))
+	}
+	if !reflect.DeepEqual(map[int]int64{123: 1000}, report.ByApp()) {
+		t.Error(unexpectedValueString("by app", map[int]int64{123: 1000}, report.ByApp()))
+	}
+	if !reflect.DeepEqual(map[int]float64{12: 70, 34: 20, 0: 10}, report.PlatformShares()) {
+		t.Error(unexpectedValueString("shares", map[int]float64{12: 70, 34: 20, 0: 10}, report.PlatformShares()))
+	}
+	expectedTimeline := []ReportTotal{
+		{Time: decisionTestStart, Count: 800},
+		{Time: decisionTestStart.Add(time.Hour), Count: 200},
+	}
+	if !reflect.DeepEqual(expectedTimeline, report.Timeline()) {
+		t.Error(unexpectedValueString("timeline", expectedTimeline, report.Timeline()))
+	}
+	if err := testValues("peak", expectedTimeline[0], report.Peak()); err != nil {
+		t.Error(err)
+	}
+	byPlatformCountry := report.Aggregate(func(row *DecisionRow) string {
+		return fmt.Sprintf("%d/%s", row.PlatformId, row.Country)
+	})
+	if err := testValues("12/US", int64(100), byPlatformCountry["12/US"]); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecisionQueryValidation(t *testing.T) {
+	_, err := client.OpenmixReporting.Decisions(&DecisionQuery{Interval: "1w", PageSize: -1})
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	expected := []FieldError{
+		{"start", "is required"},
+		{"end", "is required"},
+		{"interval", "must be one of 5m, 1h, 1d"},
+		{"pageSize", "must not be negative"},
+	}
+	if !reflect.DeepEqual(expected, validationErr.Fields) {
+		t.Error(unexpectedValueString("field errors", expected, validationErr.Fields))
+	}
+	if report := (&DecisionReport{}); len(report.PlatformShares()) != 0 || report.Peak().Count != 0 {
+		t.Error("Expected an empty report to have no shares and no peak")
+	}
+}
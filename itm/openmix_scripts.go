@@ -0,0 +1,361 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultScriptTTL is the DNS TTL, in seconds, used by generated scripts
+// that do not set one
+const DefaultScriptTTL = 20
+
+// ScriptPlatform identifies a Platform in a generated Openmix script. Alias
+// is the provider alias Openmix knows the Platform by, i.e. its name.
+type ScriptPlatform struct {
+	Alias string
+	Id    int
+	Cname string
+}
+
+// AppScript generates the appData of a custom JavaScript Openmix
+// Application implementing a standard routing strategy. Generated scripts
+// only depend on the configuration, so they can be kept under version
+// control and compared.
+type AppScript interface {
+	Script() (string, error)
+	Platforms() []ScriptPlatform
+}
+
+// NewScriptDNSAppOpts creates and returns a new validated DNSAppOpts struct
+// for a custom JavaScript application running the generated script
+func NewScriptDNSAppOpts(name string, description string, fallback string, script AppScript) (DNSAppOpts, error) {
+	appData, err := script.Script()
+	if err != nil {
+		return DNSAppOpts{}, err
+	}
+	var platforms []PlatformRef
+	for _, current := range script.Platforms() {
+		platforms = append(platforms, NewPlatformRef(current.Id, current.Cname))
+	}
+	return NewValidatedDNSAppOpts(name, appData, description, fallback, platforms, AppTypeCustomJavaScript, ProtocolDNS, 0)
+}
+
+// WeightedPlatform is a Platform receiving a share of requests proportional
+// to Weight
+type WeightedPlatform struct {
+	ScriptPlatform
+	Weight int
+}
+
+// WeightedRoundRobin spreads requests randomly across platforms according
+// to their weights
+type WeightedRoundRobin struct {
+	Targets []WeightedPlatform
+	TTL     int
+}
+
+// Platforms implements AppScript
+func (s *WeightedRoundRobin) Platforms() []ScriptPlatform {
+	var result []ScriptPlatform
+	for _, current := range s.Targets {
+		result = append(result, current.ScriptPlatform)
+	}
+	return result
+}
+
+// Script implements AppScript
+func (s *WeightedRoundRobin) Script() (string, error) {
+	if err := checkScriptPlatforms(s.Platforms()); err != nil {
+		return "", err
+	}
+	type provider struct {
+		Alias  string `json:"alias"`
+		Cname  string `json:"cname"`
+		Weight int    `json:"weight"`
+	}
+	var providers []provider
+	total := 0
+	for _, current := range s.Targets {
+		if current.Weight < 0 {
+			return "", fmt.Errorf("Negative weight for platform %s", current.Alias)
+		}
+		total += current.Weight
+		providers = append(providers, provider{current.Alias, current.Cname, current.Weight})
+	}
+	if total == 0 {
+		return "", fmt.Errorf("At least one platform needs a positive weight")
+	}
+	return renderScript("weighted round robin", s.Platforms(), []scriptVar{
+		{"providers", providers},
+		{"ttl", scriptTTL(s.TTL)},
+	}, weightedRoundRobinHandlers)
+}
+
+const weightedRoundRobinHandlers = `function onRequest(request, response) {
+    var total = 0;
+    var i;
+    for (i = 0; i < providers.length; i++) {
+        total += providers[i].weight;
+    }
+    var pick = Math.random() * total;
+    for (i = 0; i < providers.length; i++) {
+        pick -= providers[i].weight;
+        if (pick < 0) {
+            break;
+        }
+    }
+    var chosen = providers[Math.min(i, providers.length - 1)];
+    response.respond(chosen.alias, chosen.cname);
+    response.setTTL(ttl);
+    response.setReasonCode('A');
+}`
+
+// StaticCountryRouting routes requests by the ISO country code of the
+// resolver, falling back to Default for unlisted countries
+type StaticCountryRouting struct {
+	Targets []ScriptPlatform
+	// Routes maps ISO 3166 country codes to platform aliases
+	Routes  map[string]string
+	Default string
+	TTL     int
+}
+
+// Platforms implements AppScript
+func (s *StaticCountryRouting) Platforms() []ScriptPlatform {
+	return s.Targets
+}
+
+// Script implements AppScript
+func (s *StaticCountryRouting) Script() (string, error) {
+	if err := checkScriptPlatforms(s.Targets); err != nil {
+		return "", err
+	}
+	cnames := make(map[string]string)
+	for _, current := range s.Targets {
+		cnames[current.Alias] = current.Cname
+	}
+	if _, ok := cnames[s.Default]; !ok {
+		return "", fmt.Errorf("Unknown default platform %q", s.Default)
+	}
+	var countries []string
+	for country := range s.Routes {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	routes := make(map[string]string)
+	for _, country := range countries {
+		alias := s.Routes[country]
+		if _, ok := cnames[alias]; !ok {
+			return "", fmt.Errorf("Unknown platform %q for country %s", alias, country)
+		}
+		routes[strings.ToUpper(country)] = alias
+	}
+	return renderScript("static country routing", s.Targets, []scriptVar{
+		{"cnames", cnames},
+		{"routes", routes},
+		{"defaultProvider", s.Default},
+		{"ttl", scriptTTL(s.TTL)},
+	}, staticCountryRoutingHandlers)
+}
+
+const staticCountryRoutingHandlers = `function onRequest(request, response) {
+    var alias = routes[request.country];
+    var reason = 'A';
+    if (alias === undefined) {
+        alias = defaultProvider;
+        reason = 'B';
+    }
+    response.respond(alias, cnames[alias]);
+    response.setTTL(ttl);
+    response.setReasonCode(reason);
+}`
+
+// FailoverRouting routes requests to Primary while Sonar reports it as
+// available, then to the first available of Backups, in order. When no
+// platform is available, requests go to Primary.
+type FailoverRouting struct {
+	Primary ScriptPlatform
+	Backups []ScriptPlatform
+	TTL     int
+}
+
+// Platforms implements AppScript
+func (s *FailoverRouting) Platforms() []ScriptPlatform {
+	return append([]ScriptPlatform{s.Primary}, s.Backups...)
+}
+
+// Script implements AppScript
+func (s *FailoverRouting) Script() (string, error) {
+	platforms := s.Platforms()
+	if err := checkScriptPlatforms(platforms); err != nil {
+		return "", err
+	}
+	return renderScript("primary/backup failover", platforms, []scriptVar{
+		{"providers", scriptProviders(platforms)},
+		{"ttl", scriptTTL(s.TTL)},
+	}, failoverHandlers)
+}
+
+const failoverHandlers = `function isAvailable(sonar, alias) {
+    if (sonar === undefined || sonar[alias] === undefined) {
+        return false;
+    }
+    try {
+        return JSON.parse(sonar[alias]).avail > 0;
+    } catch (e) {
+        return false;
+    }
+}
+
+function onRequest(request, response) {
+    var sonar = request.getData('sonar');
+    var chosen = providers[0];
+    var reason = 'C';
+    for (var i = 0; i < providers.length; i++) {
+        if (isAvailable(sonar, providers[i].alias)) {
+            chosen = providers[i];
+            reason = i === 0 ? 'A' : 'B';
+            break;
+        }
+    }
+    response.respond(chosen.alias, chosen.cname);
+    response.setTTL(ttl);
+    response.setReasonCode(reason);
+}`
+
+// LowestRTTRouting routes requests to the platform with the lowest Radar
+// HTTP round trip time among those whose Radar availability is at least
+// AvailabilityThreshold percent. When no platform qualifies, requests go to
+// the first platform.
+type LowestRTTRouting struct {
+	Targets               []ScriptPlatform
+	AvailabilityThreshold float64
+	TTL                   int
+}
+
+// Platforms implements AppScript
+func (s *LowestRTTRouting) Platforms() []ScriptPlatform {
+	return s.Targets
+}
+
+// Script implements AppScript
+func (s *LowestRTTRouting) Script() (string, error) {
+	if err := checkScriptPlatforms(s.Targets); err != nil {
+		return "", err
+	}
+	if s.AvailabilityThreshold < 0 || s.AvailabilityThreshold > 100 {
+		return "", fmt.Errorf("Availability threshold must be between 0 and 100")
+	}
+	return renderScript("lowest round trip time", s.Targets, []scriptVar{
+		{"providers", scriptProviders(s.Targets)},
+		{"availabilityThreshold", s.AvailabilityThreshold},
+		{"ttl", scriptTTL(s.TTL)},
+	}, lowestRTTHandlers)
+}
+
+const lowestRTTHandlers = `function onRequest(request, response) {
+    var avail = request.getProbe('avail');
+    var rtt = request.getProbe('http_rtt');
+    var chosen;
+    var chosenRtt;
+    for (var i = 0; i < providers.length; i++) {
+        var alias = providers[i].alias;
+        if (avail[alias] === undefined || avail[alias].avail < availabilityThreshold) {
+            continue;
+        }
+        if (rtt[alias] === undefined) {
+            continue;
+        }
+        if (chosen === undefined || rtt[alias].http_rtt < chosenRtt) {
+            chosen = providers[i];
+            chosenRtt = rtt[alias].http_rtt;
+        }
+    }
+    var reason = 'A';
+    if (chosen === undefined) {
+        chosen = providers[0];
+        reason = 'B';
+    }
+    response.respond(chosen.alias, chosen.cname);
+    response.setTTL(ttl);
+    response.setReasonCode(reason);
+}`
+
+type scriptVar struct {
+	name  string
+	value interface{}
+}
+
+type scriptProvider struct {
+	Alias string `json:"alias"`
+	Cname string `json:"cname"`
+}
+
+func scriptProviders(platforms []ScriptPlatform) []scriptProvider {
+	var result []scriptProvider
+	for _, current := range platforms {
+		result = append(result, scriptProvider{current.Alias, current.Cname})
+	}
+	return result
+}
+
+func scriptTTL(ttl int) int {
+	if ttl <= 0 {
+		return DefaultScriptTTL
+	}
+	return ttl
+}
+
+// checkScriptPlatforms makes sure platforms can be referenced by alias
+func checkScriptPlatforms(platforms []ScriptPlatform) error {
+	if len(platforms) == 0 {
+		return fmt.Errorf("At least one platform is required")
+	}
+	seen := make(map[string]bool)
+	for _, current := range platforms {
+		if current.Alias == "" || current.Cname == "" {
+			return fmt.Errorf("Platform %d needs both an alias and a CNAME", current.Id)
+		}
+		if seen[current.Alias] {
+			return fmt.Errorf("Platform alias %s is used more than once", current.Alias)
+		}
+		seen[current.Alias] = true
+	}
+	return nil
+}
+
+// renderScript lays out a generated script: a header, the configuration as
+// JSON encoded variables, an init handler requiring every platform and the
+// strategy specific handlers. encoding/json sorts map keys, which keeps the
+// output stable.
+func renderScript(strategy string, platforms []ScriptPlatform, vars []scriptVar, handlers string) (string, error) {
+	var aliases []string
+	for _, current := range platforms {
+		aliases = append(aliases, current.Alias)
+	}
+	vars = append([]scriptVar{{"aliases", aliases}}, vars...)
+	var result strings.Builder
+	fmt.Fprintf(&result, "// Openmix %s application generated by %s. Do not edit.\n\n", strategy, libraryName)
+	for _, current := range vars {
+		value, err := json.MarshalIndent(current.value, "", "    ")
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&result, "var %s = %s;\n", current.name, value)
+	}
+	result.WriteString(scriptInitHandler)
+	result.WriteString(handlers)
+	result.WriteString("\n")
+	return result.String(), nil
+}
+
+const scriptInitHandler = `
+function init(config) {
+    for (var i = 0; i < aliases.length; i++) {
+        config.requireProvider(aliases[i]);
+    }
+}
+
+`
@@ -0,0 +1,124 @@
+package itm
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+var (
+	fooScriptPlatform = ScriptPlatform{Alias: "foo", Id: 12, Cname: "foo.com"}
+	barScriptPlatform = ScriptPlatform{Alias: "bar", Id: 34, Cname: "bar.com"}
+	bazScriptPlatform = ScriptPlatform{Alias: "baz", Id: 56, Cname: "baz.com"}
+)
+
+func testGoldenScript(t *testing.T, name string, script AppScript) {
+	got, err := script.Script()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	path := filepath.Join("testdata", "scripts", name+".js")
+	if *updateGolden {
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues(name+" script", string(expected), got); err != nil {
+		t.Error(err)
+	}
+	again, _ := script.Script()
+	if again != got {
+		t.Errorf("%s: expected deterministic output", name)
+	}
+	if issues := LintAppData(got); len(issues) != 0 {
+		t.Errorf("%s: unexpected lint issues: %v", name, issues)
+	}
+}
+
+func TestGeneratedScripts(t *testing.T) {
+	testGoldenScript(t, "weighted_round_robin", &WeightedRoundRobin{
+		Targets: []WeightedPlatform{
+			{fooScriptPlatform, 75},
+			{barScriptPlatform, 25},
+		},
+	})
+	testGoldenScript(t, "static_country_routing", &StaticCountryRouting{
+		Targets: []ScriptPlatform{fooScriptPlatform, barScriptPlatform, bazScriptPlatform},
+		Routes: map[string]string{
+			"us": "foo",
+			"FR": "bar",
+			"DE": "bar",
+		},
+		Default: "baz",
+		TTL:     60,
+	})
+	testGoldenScript(t, "failover", &FailoverRouting{
+		Primary: fooScriptPlatform,
+		Backups: []ScriptPlatform{barScriptPlatform, bazScriptPlatform},
+		TTL:     30,
+	})
+	testGoldenScript(t, "lowest_rtt", &LowestRTTRouting{
+		Targets:               []ScriptPlatform{fooScriptPlatform, barScriptPlatform},
+		AvailabilityThreshold: 90,
+	})
+}
+
+func TestGeneratedScriptErrors(t *testing.T) {
+	testData := []struct {
+		script   AppScript
+		expected string
+	}{
+		{&WeightedRoundRobin{}, "At least one platform is required"},
+		{&WeightedRoundRobin{Targets: []WeightedPlatform{{fooScriptPlatform, 0}}}, "At least one platform needs a positive weight"},
+		{&WeightedRoundRobin{Targets: []WeightedPlatform{{fooScriptPlatform, -1}}}, "Negative weight for platform foo"},
+		{&FailoverRouting{Primary: fooScriptPlatform, Backups: []ScriptPlatform{fooScriptPlatform}}, "Platform alias foo is used more than once"},
+		{&LowestRTTRouting{Targets: []ScriptPlatform{{Alias: "foo", Id: 12}}}, "Platform 12 needs both an alias and a CNAME"},
+		{&LowestRTTRouting{Targets: []ScriptPlatform{fooScriptPlatform}, AvailabilityThreshold: 120}, "Availability threshold must be between 0 and 100"},
+		{&StaticCountryRouting{Targets: []ScriptPlatform{fooScriptPlatform}, Default: "bar"}, `Unknown default platform "bar"`},
+		{&StaticCountryRouting{Targets: []ScriptPlatform{fooScriptPlatform}, Routes: map[string]string{"FR": "bar"}, Default: "foo"}, `Unknown platform "bar" for country FR`},
+	}
+	for _, current := range testData {
+		_, err := current.script.Script()
+		if err == nil {
+			t.Errorf("Expected error %q", current.expected)
+			continue
+		}
+		if err := testValues("error", current.expected, err.Error()); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestNewScriptDNSAppOpts(t *testing.T) {
+	script := &FailoverRouting{
+		Primary: fooScriptPlatform,
+		Backups: []ScriptPlatform{barScriptPlatform},
+	}
+	opts, err := NewScriptDNSAppOpts("foo", "foo description", "fallback.foo.com", script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appData, _ := script.Script()
+	if err := testValues("app data", appData[:len(appData)-1], opts.AppData); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("type", AppTypeCustomJavaScript, opts.Type); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("platform count", 2, len(opts.Platforms)); err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("backup platform", 34, opts.Platforms[1].Id); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewScriptDNSAppOpts("foo", "", "", script); err == nil {
+		t.Error("Expected a validation error without fallback CNAME")
+	}
+}
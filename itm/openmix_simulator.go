@@ -0,0 +1,205 @@
+package itm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Measurement is a Radar measurement of a Platform as seen from a location,
+// such as a country code or a market
+type Measurement struct {
+	PlatformId int
+	Location   string
+	// RTT is the HTTP round trip time in milliseconds
+	RTT float64
+	// Throughput is in kilobits per second
+	Throughput float64
+	// Availability is a percentage
+	Availability float64
+}
+
+// MeasurementTable holds the Radar measurements a simulation is based on.
+// Each Platform should appear at most once per location.
+type MeasurementTable []Measurement
+
+// Locations returns the distinct locations of the table, sorted
+func (t MeasurementTable) Locations() []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, current := range t {
+		if !seen[current.Location] {
+			seen[current.Location] = true
+			result = append(result, current.Location)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (t MeasurementTable) lookup(location string, platformId int) (Measurement, bool) {
+	for _, current := range t {
+		if current.Location == location && current.PlatformId == platformId {
+			return current, true
+		}
+	}
+	return Measurement{}, false
+}
+
+// SimulatedCandidate explains why a Platform was or was not eligible in a
+// simulation
+type SimulatedCandidate struct {
+	PlatformId int
+	Eligible   bool
+	Reason     string
+}
+
+// SimulationResult is the routing decision predicted for one location. When
+// no Platform is eligible, Fallback is set, PlatformId is zero and Cname is
+// the fallback CNAME of the application. For AppTypeRoundRobin, Shares holds
+// the percentage of the requests each eligible Platform receives, and
+// PlatformId is the one receiving the most.
+type SimulationResult struct {
+	Location   string
+	PlatformId int
+	Cname      string
+	Fallback   bool
+	Shares     map[int]float64
+	Candidates []SimulatedCandidate
+}
+
+// SimulateDNSApp predicts which Platform an Openmix Application of a
+// built-in type would choose for requests from location. Disabled platforms
+// and platforms with a zero weight are never eligible.
+//
+// For the performance types, which apply the availability threshold,
+// platforms without measurements and platforms whose availability is below
+// the threshold are not eligible either. Among the others, the lowest RTT
+// wins for AppTypeOptimalRTT and the highest throughput for
+// AppTypeThroughput, ties going to the platform listed first.
+//
+// AppTypeStaticRouting routes to the first eligible platform, and
+// AppTypeRoundRobin spreads requests across the eligible platforms in
+// proportion to their weights, a platform without a weight counting as 1.
+// Neither type has an availability threshold, so measurements are not
+// needed and availability is not considered.
+//
+// Custom JavaScript applications cannot be simulated and return an error.
+func SimulateDNSApp(app *DNSApp, table MeasurementTable, location string) (*SimulationResult, error) {
+	switch app.Type {
+	case AppTypeStaticRouting, AppTypeRoundRobin:
+		return simulateWithoutMeasurements(app, location), nil
+	}
+	if !app.Type.UsesAvailabilityThreshold() {
+		return nil, fmt.Errorf("Simulation is not supported for %s applications", app.Type)
+	}
+	result := &SimulationResult{Location: location}
+	var best *Measurement
+	var bestRef PlatformRef
+	for _, ref := range app.Platforms {
+		candidate := SimulatedCandidate{PlatformId: ref.Id}
+		measurement, ok := table.lookup(location, ref.Id)
+		switch {
+		case !ref.IsEnabled():
+			candidate.Reason = "disabled"
+		case ref.Weight != nil && *ref.Weight == 0:
+			candidate.Reason = "zero weight"
+		case !ok:
+			candidate.Reason = "no measurement"
+		case measurement.Availability < float64(app.AvlThreshold):
+			candidate.Reason = fmt.Sprintf("availability %.2f%% below threshold %d%%", measurement.Availability, app.AvlThreshold)
+		default:
+			candidate.Eligible = true
+			if app.Type == AppTypeOptimalRTT {
+				candidate.Reason = fmt.Sprintf("RTT %.2f ms", measurement.RTT)
+			} else {
+				candidate.Reason = fmt.Sprintf("throughput %.2f kbps", measurement.Throughput)
+			}
+			if best == nil || betterMeasurement(app.Type, &measurement, best) {
+				current := measurement
+				best = &current
+				bestRef = ref
+			}
+		}
+		result.Candidates = append(result.Candidates, candidate)
+	}
+	if best == nil {
+		result.Fallback = true
+		result.Cname = app.FallbackCname
+		return result, nil
+	}
+	result.PlatformId = bestRef.Id
+	result.Cname = bestRef.Cname
+	return result, nil
+}
+
+// simulateWithoutMeasurements simulates the static routing and round robin
+// types, which only depend on the platform settings
+func simulateWithoutMeasurements(app *DNSApp, location string) *SimulationResult {
+	result := &SimulationResult{Location: location}
+	weights := make(map[int]int)
+	var eligible []PlatformRef
+	total := 0
+	for _, ref := range app.Platforms {
+		candidate := SimulatedCandidate{PlatformId: ref.Id}
+		weight := 1
+		if ref.Weight != nil {
+			weight = *ref.Weight
+		}
+		switch {
+		case !ref.IsEnabled():
+			candidate.Reason = "disabled"
+		case weight == 0:
+			candidate.Reason = "zero weight"
+		default:
+			candidate.Eligible = true
+			if app.Type == AppTypeStaticRouting {
+				candidate.Reason = "enabled"
+			} else {
+				candidate.Reason = fmt.Sprintf("weight %d", weight)
+			}
+			eligible = append(eligible, ref)
+			weights[ref.Id] = weight
+			total += weight
+		}
+		result.Candidates = append(result.Candidates, candidate)
+	}
+	if len(eligible) == 0 {
+		result.Fallback = true
+		result.Cname = app.FallbackCname
+		return result
+	}
+	chosen := eligible[0]
+	if app.Type == AppTypeRoundRobin {
+		result.Shares = make(map[int]float64)
+		for _, ref := range eligible {
+			result.Shares[ref.Id] = 100 * float64(weights[ref.Id]) / float64(total)
+			if weights[ref.Id] > weights[chosen.Id] {
+				chosen = ref
+			}
+		}
+	}
+	result.PlatformId = chosen.Id
+	result.Cname = chosen.Cname
+	return result
+}
+
+// SimulateDNSAppLocations runs SimulateDNSApp for every location of the
+// table
+func SimulateDNSAppLocations(app *DNSApp, table MeasurementTable) ([]SimulationResult, error) {
+	var result []SimulationResult
+	for _, location := range table.Locations() {
+		current, err := SimulateDNSApp(app, table, location)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *current)
+	}
+	return result, nil
+}
+
+func betterMeasurement(appType AppType, candidate *Measurement, best *Measurement) bool {
+	if appType == AppTypeOptimalRTT {
+		return candidate.RTT < best.RTT
+	}
+	return candidate.Throughput > best.Throughput
+}
@@ -0,0 +1,158 @@
+package itm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func simulatorTestApp(appType AppType) *DNSApp {
+	disabled := false
+	zero := 0
+	return &DNSApp{
+		Id:            123,
+		Name:          "foo",
+		Type:          appType,
+		Protocol:      ProtocolDNS,
+		FallbackCname: "fallback.foo.com",
+		AvlThreshold:  90,
+		Platforms: []PlatformRef{
+			NewPlatformRef(1, "one.com"),
+			NewPlatformRef(2, "two.com"),
+			{Id: 3, Cname: "three.com", Enabled: &disabled},
+			{Id: 4, Cname: "four.com", Weight: &zero},
+			NewPlatformRef(5, "five.com"),
+		},
+	}
+}
+
+var simulatorTestTable = MeasurementTable{
+	{PlatformId: 1, Location: "FR", RTT: 80, Throughput: 9000, Availability: 99},
+	{PlatformId: 2, Location: "FR", RTT: 50, Throughput: 4000, Availability: 95},
+	{PlatformId: 3, Location: "FR", RTT: 10, Throughput: 20000, Availability: 100},
+	{PlatformId: 4, Location: "FR", RTT: 10, Throughput: 20000, Availability: 100},
+	{PlatformId: 1, Location: "US", RTT: 40, Throughput: 7000, Availability: 80},
+	{PlatformId: 2, Location: "US", RTT: 60, Throughput: 3000, Availability: 85},
+	{PlatformId: 5, Location: "US", RTT: 30, Throughput: 3000, Availability: 89.5},
+}
+
+func TestSimulateDNSAppOptimalRTT(t *testing.T) {
+	result, err := SimulateDNSApp(simulatorTestApp(AppTypeOptimalRTT), simulatorTestTable, "FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &SimulationResult{
+		Location:   "FR",
+		PlatformId: 2,
+		Cname:      "two.com",
+		Candidates: []SimulatedCandidate{
+			{1, true, "RTT 80.00 ms"},
+			{2, true, "RTT 50.00 ms"},
+			{3, false, "disabled"},
+			{4, false, "zero weight"},
+			{5, false, "no measurement"},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Error(unexpectedValueString("simulation", expected, result))
+	}
+}
+
+func TestSimulateDNSAppThroughput(t *testing.T) {
+	result, err := SimulateDNSApp(simulatorTestApp(AppTypeThroughput), simulatorTestTable, "FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("platform", 1, result.PlatformId); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSimulateDNSAppFallback(t *testing.T) {
+	results, err := SimulateDNSAppLocations(simulatorTestApp(AppTypeOptimalRTT), simulatorTestTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("result count", 2, len(results)); err != nil {
+		t.Fatal(err)
+	}
+	us := results[1]
+	if err := testValues("location", "US", us.Location); err != nil {
+		t.Error(err)
+	}
+	if !us.Fallback {
+		t.Error("Expected fallback when every platform is below the availability threshold")
+	}
+	if err := testValues("cname", "fallback.foo.com", us.Cname); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("reason", "availability 89.50% below threshold 90%", us.Candidates[4].Reason); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSimulateDNSAppUnsupportedType(t *testing.T) {
+	_, err := SimulateDNSApp(simulatorTestApp(AppTypeCustomJavaScript), simulatorTestTable, "FR")
+	if err == nil {
+		t.Fatal("Expected an error for custom JavaScript applications")
+	}
+	if err := testValues("error", "Simulation is not supported for V1_JS applications", err.Error()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSimulateDNSAppStaticRouting(t *testing.T) {
+	app := simulatorTestApp(AppTypeStaticRouting)
+	disabled := false
+	app.Platforms[0].Enabled = &disabled
+	// Static routing needs no measurement, so any location works
+	result, err := SimulateDNSApp(app, nil, "JP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("platform", 2, result.PlatformId); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("cname", "two.com", result.Cname); err != nil {
+		t.Error(err)
+	}
+	if result.Shares != nil || result.Fallback {
+		t.Errorf("Unexpected static routing result: %+v", result)
+	}
+}
+
+func TestSimulateDNSAppRoundRobin(t *testing.T) {
+	app := simulatorTestApp(AppTypeRoundRobin)
+	three := 3
+	app.Platforms[1].Weight = &three
+	result, err := SimulateDNSApp(app, simulatorTestTable, "FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &SimulationResult{
+		Location:   "FR",
+		PlatformId: 2,
+		Cname:      "two.com",
+		Shares:     map[int]float64{1: 20, 2: 60, 5: 20},
+		Candidates: []SimulatedCandidate{
+			{1, true, "weight 1"},
+			{2, true, "weight 3"},
+			{3, false, "disabled"},
+			{4, false, "zero weight"},
+			{5, true, "weight 1"},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Error(unexpectedValueString("simulation", expected, result))
+	}
+	for i := range app.Platforms {
+		disabled := false
+		app.Platforms[i].Enabled = &disabled
+	}
+	result, err = SimulateDNSApp(app, simulatorTestTable, "FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Fallback || result.Cname != "fallback.foo.com" {
+		t.Errorf("Expected fallback when every platform is disabled, got %+v", result)
+	}
+}
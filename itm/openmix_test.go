@@ -0,0 +1,39 @@
+package itm
+
+import "testing"
+
+func TestAppTypeIsValid(t *testing.T) {
+	testData := []struct {
+		appType   AppType
+		valid     bool
+		builtIn   bool
+		threshold bool
+	}{
+		{AppTypeCustomJavaScript, true, false, false},
+		{AppTypeOptimalRTT, true, true, true},
+		{AppTypeThroughput, true, true, true},
+		{AppTypeRoundRobin, true, true, false},
+		{AppTypeStaticRouting, true, true, false},
+		{"RT_HTTP_PERFORMANCEE", false, false, false},
+	}
+	for _, current := range testData {
+		if err := testValues(string(current.appType)+" valid", current.valid, current.appType.IsValid()); err != nil {
+			t.Error(err)
+		}
+		if err := testValues(string(current.appType)+" built-in", current.builtIn, current.appType.IsBuiltIn()); err != nil {
+			t.Error(err)
+		}
+		if err := testValues(string(current.appType)+" uses threshold", current.threshold, current.appType.UsesAvailabilityThreshold()); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestProtocolIsValid(t *testing.T) {
+	if !ProtocolDNS.IsValid() || !ProtocolHTTP.IsValid() {
+		t.Error("Expected known protocols to be valid")
+	}
+	if Protocol("dsn").IsValid() {
+		t.Error("Expected unknown protocol to be invalid")
+	}
+}
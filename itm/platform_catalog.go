@@ -0,0 +1,203 @@
+package itm
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+)
+
+const (
+	platformCategoriesPath = "v2/config/platforms/categories.json"
+	platformArchetypesPath = "v2/config/platforms/archetypes.json"
+)
+
+// PlatformArchetype is a public provider, such as a CDN or a cloud region,
+// whose Radar community data a Platform can use
+type PlatformArchetype struct {
+	Id          int               `json:"id"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"displayName,omitempty"`
+	Category    *PlatformCategory `json:"category,omitempty"`
+}
+
+// copy returns a deep copy of the archetype, so that it can be handed out
+// from the cache
+func (a PlatformArchetype) copy() PlatformArchetype {
+	a.Category = a.Category.copy()
+	return a
+}
+
+type catalogService interface {
+	Categories() ([]PlatformCategory, error)
+	Archetypes() ([]PlatformArchetype, error)
+	CategoryByName(string) (*PlatformCategory, error)
+	ArchetypeByName(string) (*PlatformArchetype, error)
+	PublicPlatformOpts(string, string) (*PlatformOpts, error)
+	PrivatePlatformOpts(string, string) (*PlatformOpts, error)
+	Refresh()
+}
+
+// catalogServiceImpl loads categories and archetypes once and keeps them
+// until Refresh is called, as they rarely change
+type catalogServiceImpl struct {
+	client     *Client
+	mutex      sync.Mutex
+	categories []PlatformCategory
+	archetypes []PlatformArchetype
+}
+
+// Categories lists the Platform categories. The returned slice is a copy of
+// the cache.
+func (s *catalogServiceImpl) Categories() ([]PlatformCategory, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.categories == nil {
+		var result []PlatformCategory
+		if err := s.load(platformCategoriesPath, &result); err != nil {
+			return nil, err
+		}
+		s.categories = result
+	}
+	result := make([]PlatformCategory, len(s.categories))
+	for i := range s.categories {
+		result[i] = *s.categories[i].copy()
+	}
+	return result, nil
+}
+
+// Archetypes lists the public provider archetypes. The returned slice is a
+// copy of the cache.
+func (s *catalogServiceImpl) Archetypes() ([]PlatformArchetype, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.archetypes == nil {
+		var result []PlatformArchetype
+		if err := s.load(platformArchetypesPath, &result); err != nil {
+			return nil, err
+		}
+		s.archetypes = result
+	}
+	result := make([]PlatformArchetype, len(s.archetypes))
+	for i := range s.archetypes {
+		result[i] = s.archetypes[i].copy()
+	}
+	return result, nil
+}
+
+// CategoryByName returns the Platform category with the given name, ignoring
+// case
+func (s *catalogServiceImpl) CategoryByName(name string) (*PlatformCategory, error) {
+	categories, err := s.Categories()
+	if err != nil {
+		return nil, err
+	}
+	var found []int
+	for i, current := range categories {
+		if strings.EqualFold(current.Name, name) {
+			found = append(found, i)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, &NotFoundError{Resource: "Platform category", Field: "name", Value: name}
+	case 1:
+		return &categories[found[0]], nil
+	}
+	ids := make([]int, len(found))
+	for i, index := range found {
+		ids[i] = categories[index].Id
+	}
+	return nil, &AmbiguousError{Resource: "Platform category", Field: "name", Value: name, Ids: ids}
+}
+
+// ArchetypeByName returns the public provider archetype with the given name
+// or display name, ignoring case
+func (s *catalogServiceImpl) ArchetypeByName(name string) (*PlatformArchetype, error) {
+	archetypes, err := s.Archetypes()
+	if err != nil {
+		return nil, err
+	}
+	var found []int
+	for i, current := range archetypes {
+		if strings.EqualFold(current.Name, name) || strings.EqualFold(current.DisplayName, name) {
+			found = append(found, i)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, &NotFoundError{Resource: "Platform archetype", Field: "name", Value: name}
+	case 1:
+		return &archetypes[found[0]], nil
+	}
+	ids := make([]int, len(found))
+	for i, index := range found {
+		ids[i] = archetypes[index].Id
+	}
+	return nil, &AmbiguousError{Resource: "Platform archetype", Field: "name", Value: name, Ids: ids}
+}
+
+// PublicPlatformOpts returns the settings of a Platform measured with the
+// Radar community data of the named public provider archetype. The category
+// is the one of the archetype.
+func (s *catalogServiceImpl) PublicPlatformOpts(name string, archetypeName string) (*PlatformOpts, error) {
+	archetype, err := s.ArchetypeByName(archetypeName)
+	if err != nil {
+		return nil, err
+	}
+	return &PlatformOpts{
+		Name:                      name,
+		DisplayName:               name,
+		Category:                  archetype.Category,
+		RadarOpts:                 &RadarConfig{UsePublicData: true},
+		SonarOpts:                 &SonarConfig{},
+		Enabled:                   true,
+		OpenMixEnabled:            true,
+		OpenmixVisible:            true,
+		PublicProviderArchetypeId: archetype.Id,
+	}, nil
+}
+
+// PrivatePlatformOpts returns the settings of a private Platform of the named
+// category, such as a data center. Radar and Sonar are left disabled, ready
+// to be configured.
+func (s *catalogServiceImpl) PrivatePlatformOpts(name string, categoryName string) (*PlatformOpts, error) {
+	category, err := s.CategoryByName(categoryName)
+	if err != nil {
+		return nil, err
+	}
+	return &PlatformOpts{
+		Name:           name,
+		DisplayName:    name,
+		Category:       category,
+		RadarOpts:      &RadarConfig{},
+		SonarOpts:      &SonarConfig{},
+		Enabled:        true,
+		OpenMixEnabled: true,
+		OpenmixVisible: true,
+		IsPrivate:      true,
+	}, nil
+}
+
+// Refresh empties the cache, so that the next calls reload the catalog
+func (s *catalogServiceImpl) Refresh() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.categories = nil
+	s.archetypes = nil
+}
+
+func (s *catalogServiceImpl) load(path string, result interface{}) error {
+	resp, err := s.client.get(path)
+	if err != nil {
+		log.Printf("Error issuing get request from CatalogServiceImpl: %v", err)
+		return err
+	}
+	if 200 != resp.StatusCode {
+		return &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	return json.Unmarshal(resp.Body, result)
+}
@@ -0,0 +1,147 @@
+package itm
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func setupCatalog() (func(), *int) {
+	teardown := setup()
+	calls := 0
+	mux.HandleFunc("/v2/config/platforms/categories.json", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[{"id":1,"name":"Content Delivery Network","rank":1},{"id":2,"name":"Cloud Computing"},{"id":3,"name":"Data Center"}]`)
+	})
+	mux.HandleFunc("/v2/config/platforms/archetypes.json", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[
+			{"id":10,"name":"akamai","displayName":"Akamai","category":{"id":1,"name":"Content Delivery Network"}},
+			{"id":20,"name":"aws_eu_west_1","displayName":"AWS EC2 eu-west-1","category":{"id":2,"name":"Cloud Computing"}},
+			{"id":21,"name":"aws_us_east_1","displayName":"AWS","category":{"id":2}},
+			{"id":22,"name":"aws_us_west_2","displayName":"AWS","category":{"id":2}}
+		]`)
+	})
+	return teardown, &calls
+}
+
+func TestCatalogLookup(t *testing.T) {
+	teardown, calls := setupCatalog()
+	defer teardown()
+	category, err := client.Catalog.CategoryByName("cloud computing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&PlatformCategory{Id: 2, Name: "Cloud Computing"}, category) {
+		t.Error(unexpectedValueString("category", &PlatformCategory{Id: 2, Name: "Cloud Computing"}, category))
+	}
+	if _, err := client.Catalog.CategoryByName("DNS"); err == nil {
+		t.Error("Expected an error for an unknown category")
+	}
+	archetype, err := client.Catalog.ArchetypeByName("Akamai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("archetype id", 10, archetype.Id); err != nil {
+		t.Error(err)
+	}
+	archetype, err = client.Catalog.ArchetypeByName("aws_eu_west_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("archetype id", 20, archetype.Id); err != nil {
+		t.Error(err)
+	}
+	_, err = client.Catalog.ArchetypeByName("aws")
+	if err := testValues("error", `2 Platform archetypes found with name "aws": 21, 22`, fmt.Sprint(err)); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("catalog requests", 2, *calls); err != nil {
+		t.Error(err)
+	}
+	client.Catalog.Refresh()
+	if _, err := client.Catalog.Categories(); err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("catalog requests", 3, *calls); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCatalogCopies(t *testing.T) {
+	teardown, _ := setupCatalog()
+	defer teardown()
+	categories, err := client.Catalog.Categories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	categories[0].Name = "changed"
+	categories[0].Extra["rank"] = []byte("2")
+	archetypes, err := client.Catalog.Archetypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archetypes[0].Category.Name = "changed"
+	categories, _ = client.Catalog.Categories()
+	if err := testValues("category name", "Content Delivery Network", categories[0].Name); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("category rank", "1", string(categories[0].Extra["rank"])); err != nil {
+		t.Error(err)
+	}
+	archetypes, _ = client.Catalog.Archetypes()
+	if err := testValues("archetype category", "Content Delivery Network", archetypes[0].Category.Name); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCatalogPlatformOpts(t *testing.T) {
+	teardown, _ := setupCatalog()
+	defer teardown()
+	public, err := client.Catalog.PublicPlatformOpts("akamai-prod", "akamai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("archetype id", 10, public.PublicProviderArchetypeId); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("category id", 1, public.Category.Id); err != nil {
+		t.Error(err)
+	}
+	if !public.RadarOpts.UsePublicData {
+		t.Error("Expected public Radar data to be used")
+	}
+	if err := public.Validate(); err != nil {
+		t.Error(err)
+	}
+	private, err := client.Catalog.PrivatePlatformOpts("paris-dc", "data center")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("category id", 3, private.Category.Id); err != nil {
+		t.Error(err)
+	}
+	if !private.IsPrivate {
+		t.Error("Expected a private platform")
+	}
+	if err := private.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCatalogErrorStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/platforms/categories.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	_, err := client.Catalog.Categories()
+	if _, ok := err.(*UnexpectedHTTPStatusError); !ok {
+		t.Errorf("Expected *UnexpectedHTTPStatusError, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PlatformCategory identifies the category of a Platform, such as a CDN or a
+// cloud compute provider. Only the ID is needed when creating a Platform.
+type PlatformCategory struct {
+	Id   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Extra holds fields the SDK does not model, kept so that updating the
+	// Platform does not drop them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *PlatformCategory) UnmarshalJSON(data []byte) error {
+	type plain PlatformCategory
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = PlatformCategory(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v PlatformCategory) MarshalJSON() ([]byte, error) {
+	type plain PlatformCategory
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+func (c *PlatformCategory) copy() *PlatformCategory {
+	if c == nil {
+		return nil
+	}
+	result := *c
+	result.Extra = copyExtra(c.Extra)
+	return &result
+}
+
+// RadarConfig specifies how Radar measures a Platform. Public data is
+// community measurements of well known providers; private measurements are
+// taken by downloading the probe objects the Platform serves.
+type RadarConfig struct {
+	UsePublicData bool `json:"usePublicData"`
+	HTTPEnabled   bool `json:"httpEnabled"`
+	HTTPSEnabled  bool `json:"httpsEnabled"`
+	// Probe objects served over HTTP: a small object to warm up the
+	// connection, one to measure the round trip time and a large one to
+	// measure throughput
+	PrimeURL string `json:"primeUrl,omitempty"`
+	RTTURL   string `json:"rttUrl,omitempty"`
+	XLURL    string `json:"xlUrl,omitempty"`
+	// The same probe objects served over HTTPS
+	PrimeSecureURL string `json:"primeSecureUrl,omitempty"`
+	RTTSecureURL   string `json:"rttSecureUrl,omitempty"`
+	XLSecureURL    string `json:"xlSecureUrl,omitempty"`
+	// Weight is the percentage of Radar sessions measuring the Platform
+	Weight int `json:"weight,omitempty"`
+	// Extra holds fields the SDK does not model, kept so that updating the
+	// Platform does not drop them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *RadarConfig) UnmarshalJSON(data []byte) error {
+	type plain RadarConfig
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = RadarConfig(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v RadarConfig) MarshalJSON() ([]byte, error) {
+	type plain RadarConfig
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// Validate checks the Radar settings. Probe URLs must be absolute, using
+// HTTPS for the secure ones, and the weight must be a percentage.
+func (c *RadarConfig) Validate() error {
+	return newValidationError(c.fieldErrors("radarConfig"))
+}
+
+func (c *RadarConfig) fieldErrors(prefix string) []FieldError {
+	var result []FieldError
+	probes := []struct {
+		field  string
+		value  string
+		scheme string
+	}{
+		{"primeUrl", c.PrimeURL, "http"},
+		{"rttUrl", c.RTTURL, "http"},
+		{"xlUrl", c.XLURL, "http"},
+		{"primeSecureUrl", c.PrimeSecureURL, "https"},
+		{"rttSecureUrl", c.RTTSecureURL, "https"},
+		{"xlSecureUrl", c.XLSecureURL, "https"},
+	}
+	for _, current := range probes {
+		if current.value == "" {
+			continue
+		}
+		parsed, err := url.Parse(current.value)
+		if err != nil || parsed.Host == "" || parsed.Scheme != current.scheme {
+			result = append(result, FieldError{prefix + "." + current.field, fmt.Sprintf("must be an absolute %s URL", current.scheme)})
+		}
+	}
+	if c.HTTPEnabled && c.RTTURL == "" && !c.UsePublicData {
+		result = append(result, FieldError{prefix + ".rttUrl", "is required when HTTP measurements are enabled"})
+	}
+	if c.HTTPSEnabled && c.RTTSecureURL == "" && !c.UsePublicData {
+		result = append(result, FieldError{prefix + ".rttSecureUrl", "is required when HTTPS measurements are enabled"})
+	}
+	if c.Weight < 0 || c.Weight > 100 {
+		result = append(result, FieldError{prefix + ".weight", "must be between 0 and 100"})
+	}
+	return result
+}
+
+func (c *RadarConfig) copy() *RadarConfig {
+	if c == nil {
+		return nil
+	}
+	result := *c
+	result.Extra = copyExtra(c.Extra)
+	return &result
+}
+
+// SonarMethods lists the HTTP methods Sonar health checks can use
+var SonarMethods = []string{"GET", "HEAD", "POST"}
+
+// SonarConfig specifies the Sonar health check of a Platform. The URL may use
+// the http, https or tcp scheme; the method, host header, expected status and
+// expected content only apply to HTTP checks.
+type SonarConfig struct {
+	Enabled bool   `json:"enabled"`
+	URL     string `json:"url,omitempty"`
+	Method  string `json:"method,omitempty"`
+	// PollIntervalSeconds is the time between two checks
+	PollIntervalSeconds int `json:"pollIntervalSeconds,omitempty"`
+	TimeoutSeconds      int `json:"timeoutSeconds,omitempty"`
+	// ExpectedStatus is the HTTP status of a healthy response. Any 2xx or
+	// 3xx status is accepted when it is zero.
+	ExpectedStatus int `json:"expectedStatusCode,omitempty"`
+	// HostHeader overrides the Host header sent with the request
+	HostHeader string `json:"host,omitempty"`
+	// ExpectedContent, when set, must appear in the body of a healthy
+	// response
+	ExpectedContent string `json:"expectedContent,omitempty"`
+	// Extra holds fields the SDK does not model, kept so that updating the
+	// Platform does not drop them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *SonarConfig) UnmarshalJSON(data []byte) error {
+	type plain SonarConfig
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = SonarConfig(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v SonarConfig) MarshalJSON() ([]byte, error) {
+	type plain SonarConfig
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// Validate checks the Sonar settings. A disabled configuration only needs to
+// be well formed; an enabled one also needs a URL.
+func (c *SonarConfig) Validate() error {
+	return newValidationError(c.fieldErrors("sonarConfig"))
+}
+
+func (c *SonarConfig) fieldErrors(prefix string) []FieldError {
+	var result []FieldError
+	if c.URL == "" {
+		if c.Enabled {
+			result = append(result, FieldError{prefix + ".url", "is required when Sonar is enabled"})
+		}
+	} else {
+		parsed, err := url.Parse(c.URL)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "tcp") {
+			result = append(result, FieldError{prefix + ".url", "must be an absolute http, https or tcp URL"})
+		}
+	}
+	if c.Method != "" && !containsString(SonarMethods, strings.ToUpper(c.Method)) {
+		result = append(result, FieldError{prefix + ".method", "must be one of " + strings.Join(SonarMethods, ", ")})
+	}
+	if c.PollIntervalSeconds < 0 {
+		result = append(result, FieldError{prefix + ".pollIntervalSeconds", "must not be negative"})
+	}
+	if c.TimeoutSeconds < 0 {
+		result = append(result, FieldError{prefix + ".timeoutSeconds", "must not be negative"})
+	} else if c.PollIntervalSeconds > 0 && c.TimeoutSeconds > c.PollIntervalSeconds {
+		result = append(result, FieldError{prefix + ".timeoutSeconds", "must not exceed the poll interval"})
+	}
+	if c.ExpectedStatus != 0 && (c.ExpectedStatus < 100 || c.ExpectedStatus > 599) {
+		result = append(result, FieldError{prefix + ".expectedStatusCode", "must be a valid HTTP status"})
+	}
+	return result
+}
+
+func (c *SonarConfig) copy() *SonarConfig {
+	if c == nil {
+		return nil
+	}
+	result := *c
+	result.Extra = copyExtra(c.Extra)
+	return &result
+}
+
+func containsString(values []string, value string) bool {
+	for _, current := range values {
+		if current == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package itm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPlatformConfigDecoding(t *testing.T) {
+	var platform Platform
+	err := json.Unmarshal([]byte(`{"id":123,"name":"foo",
+		"category":{"id":1,"name":"Content Delivery Network"},
+		"radarConfig":{"usePublicData":false,"httpEnabled":true,"httpsEnabled":true,"rttUrl":"http://foo.com/r20.gif","rttSecureUrl":"https://foo.com/r20.gif","weight":50},
+		"sonarConfig":{"enabled":true,"url":"https://foo.com/health","method":"HEAD","pollIntervalSeconds":60,"timeoutSeconds":5,"expectedStatusCode":204,"host":"www.foo.com","marketId":3}}`), &platform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCategory := &PlatformCategory{Id: 1, Name: "Content Delivery Network"}
+	if !reflect.DeepEqual(expectedCategory, platform.Category) {
+		t.Error(unexpectedValueString("category", expectedCategory, platform.Category))
+	}
+	expectedRadar := &RadarConfig{
+		HTTPEnabled:  true,
+		HTTPSEnabled: true,
+		RTTURL:       "http://foo.com/r20.gif",
+		RTTSecureURL: "https://foo.com/r20.gif",
+		Weight:       50,
+	}
+	if !reflect.DeepEqual(expectedRadar, platform.RadarOpts) {
+		t.Error(unexpectedValueString("radar config", expectedRadar, platform.RadarOpts))
+	}
+	expectedSonar := &SonarConfig{
+		Enabled:             true,
+		URL:                 "https://foo.com/health",
+		Method:              "HEAD",
+		PollIntervalSeconds: 60,
+		TimeoutSeconds:      5,
+		ExpectedStatus:      204,
+		HostHeader:          "www.foo.com",
+		Extra:               map[string]json.RawMessage{"marketId": json.RawMessage("3")},
+	}
+	if !reflect.DeepEqual(expectedSonar, platform.SonarOpts) {
+		t.Error(unexpectedValueString("sonar config", expectedSonar, platform.SonarOpts))
+	}
+	if err := platform.RadarOpts.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := platform.SonarOpts.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPlatformToOptsCopiesConfig(t *testing.T) {
+	platform := Platform{
+		Id:        123,
+		Name:      "foo",
+		SonarOpts: &SonarConfig{Enabled: true, URL: "https://foo.com/health", Extra: map[string]json.RawMessage{"marketId": json.RawMessage("3")}},
+	}
+	opts := platform.ToOpts()
+	opts.SonarOpts.Enabled = false
+	opts.SonarOpts.Extra["marketId"] = json.RawMessage("4")
+	if !platform.SonarOpts.Enabled {
+		t.Error("Expected the platform Sonar config to be left untouched")
+	}
+	if err := testValues("market", "3", string(platform.SonarOpts.Extra["marketId"])); err != nil {
+		t.Error(err)
+	}
+	if opts.Category != nil || opts.RadarOpts != nil {
+		t.Error("Expected missing configs to stay nil")
+	}
+}
+
+func TestPlatformConfigValidation(t *testing.T) {
+	testData := []struct {
+		name     string
+		opts     PlatformOpts
+		expected []FieldError
+	}{
+		{
+			"valid",
+			PlatformOpts{
+				Name:      "foo",
+				Category:  &PlatformCategory{Id: 1},
+				RadarOpts: &RadarConfig{UsePublicData: true, HTTPEnabled: true},
+				SonarOpts: &SonarConfig{Enabled: true, URL: "tcp://foo.com:443"},
+			},
+			nil,
+		},
+		{
+			"missing",
+			PlatformOpts{SonarOpts: &SonarConfig{Enabled: true}},
+			[]FieldError{
+				{"name", "is required"},
+				{"category.id", "is required"},
+				{"sonarConfig.url", "is required when Sonar is enabled"},
+			},
+		},
+		{
+			"radar",
+			PlatformOpts{
+				Name:     "foo",
+				Category: &PlatformCategory{Id: 1},
+				RadarOpts: &RadarConfig{
+					HTTPSEnabled: true,
+					RTTURL:       "foo.com/r20.gif",
+					XLSecureURL:  "http://foo.com/100kb.jpg",
+					Weight:       120,
+				},
+			},
+			[]FieldError{
+				{"radarConfig.rttUrl", "must be an absolute http URL"},
+				{"radarConfig.xlSecureUrl", "must be an absolute https URL"},
+				{"radarConfig.rttSecureUrl", "is required when HTTPS measurements are enabled"},
+				{"radarConfig.weight", "must be between 0 and 100"},
+			},
+		},
+		{
+			"sonar",
+			PlatformOpts{
+				Name:     "foo",
+				Category: &PlatformCategory{Id: 1},
+				SonarOpts: &SonarConfig{
+					URL:                 "ftp://foo.com",
+					Method:              "DELETE",
+					PollIntervalSeconds: 10,
+					TimeoutSeconds:      30,
+					ExpectedStatus:      42,
+				},
+			},
+			[]FieldError{
+				{"sonarConfig.url", "must be an absolute http, https or tcp URL"},
+				{"sonarConfig.method", "must be one of GET, HEAD, POST"},
+				{"sonarConfig.timeoutSeconds", "must not exceed the poll interval"},
+				{"sonarConfig.expectedStatusCode", "must be a valid HTTP status"},
+			},
+		},
+	}
+	for _, current := range testData {
+		err := current.opts.Validate()
+		if current.expected == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", current.name, err)
+			}
+			continue
+		}
+		validationErr, ok := err.(*ValidationError)
+		if !ok {
+			t.Errorf("%s: expected *ValidationError, got %v", current.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(current.expected, validationErr.Fields) {
+			t.Error(unexpectedValueString(current.name+" field errors", current.expected, validationErr.Fields))
+		}
+	}
+}
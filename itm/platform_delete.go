@@ -0,0 +1,145 @@
+package itm
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// SafeDeleteOptions controls how Platform.SafeDelete handles the Openmix
+// Applications still routing to the Platform
+type SafeDeleteOptions struct {
+	// Detach removes the Platform from the applications referencing it
+	// before deleting it. Without it, a Platform in use is not deleted.
+	Detach bool
+	// Publish publishes the applications the Platform is detached from. It
+	// is required along with Detach, as a draft would leave the published
+	// applications routing to the deleted Platform.
+	Publish bool
+	// DryRun only reports the impact, without changing anything
+	DryRun bool
+}
+
+// PlatformUsage is an Openmix Application referencing a Platform
+type PlatformUsage struct {
+	AppId    int
+	AppName  string
+	Protocol Protocol
+	// Detached is set once the Platform has been removed from the
+	// application
+	Detached bool
+}
+
+// PlatformDeleteReport describes the impact of deleting a Platform
+type PlatformDeleteReport struct {
+	PlatformId int
+	Usages     []PlatformUsage
+	Deleted    bool
+}
+
+// PlatformInUseError is returned when deleting a Platform that Openmix
+// Applications still reference
+type PlatformInUseError struct {
+	PlatformId int
+	Usages     []PlatformUsage
+	// Reason is set when detaching the Platform is not possible either
+	Reason string
+}
+
+func (e PlatformInUseError) Error() string {
+	var apps []string
+	for _, current := range e.Usages {
+		apps = append(apps, fmt.Sprintf("%s application %d (%s)", current.Protocol, current.AppId, current.AppName))
+	}
+	message := fmt.Sprintf("Platform %d is used by %s", e.PlatformId, strings.Join(apps, ", "))
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+// SafeDelete deletes a Platform, first checking that no DNS or HTTP Openmix
+// Application routes to it. When some do, nothing is changed and a
+// *PlatformInUseError is returned, unless opts.Detach is set, in which case
+// the Platform is removed from those applications first. Detaching is
+// refused when it would leave a built-in application without any platform.
+// Custom JavaScript applications may still mention the Platform in their
+// script and should be reviewed. The report lists every application
+// concerned, including when an error is returned. Nothing is deleted when
+// the applications cannot be listed.
+func (s *platformServiceImpl) SafeDelete(id int, opts SafeDeleteOptions) (*PlatformDeleteReport, error) {
+	if opts.Detach && !opts.Publish && !opts.DryRun {
+		return nil, newValidationError([]FieldError{{"publish", "is required when detaching"}})
+	}
+	report := &PlatformDeleteReport{PlatformId: id}
+	dnsApps, err := s.client.DNSApps.List(DNSAppUsesPlatform(id))
+	if err != nil {
+		return nil, err
+	}
+	httpApps, err := s.client.HTTPApps.List(HTTPAppUsesPlatform(id))
+	if err != nil {
+		return nil, err
+	}
+	var lastPlatform []string
+	for _, current := range dnsApps {
+		report.Usages = append(report.Usages, PlatformUsage{AppId: current.Id, AppName: current.Name, Protocol: ProtocolDNS})
+		if current.Type.IsBuiltIn() && len(current.Platforms) == 1 {
+			lastPlatform = append(lastPlatform, current.Name)
+		}
+	}
+	for _, current := range httpApps {
+		report.Usages = append(report.Usages, PlatformUsage{AppId: current.Id, AppName: current.Name, Protocol: ProtocolHTTP})
+		if current.Type.IsBuiltIn() && len(current.Platforms) == 1 {
+			lastPlatform = append(lastPlatform, current.Name)
+		}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+	if len(report.Usages) > 0 {
+		if !opts.Detach {
+			return report, &PlatformInUseError{PlatformId: id, Usages: report.Usages}
+		}
+		if len(lastPlatform) > 0 {
+			return report, &PlatformInUseError{
+				PlatformId: id,
+				Usages:     report.Usages,
+				Reason:     "it is the only platform of " + strings.Join(lastPlatform, ", "),
+			}
+		}
+	}
+	for i := range report.Usages {
+		usage := &report.Usages[i]
+		if usage.Protocol == ProtocolDNS {
+			_, err = s.client.DNSApps.Modify(usage.AppId, func(appOpts *DNSAppOpts) error {
+				appOpts.Platforms = withoutPlatform(appOpts.Platforms, id)
+				return nil
+			}, opts.Publish)
+		} else {
+			_, err = s.client.HTTPApps.Modify(usage.AppId, func(appOpts *HTTPAppOpts) error {
+				appOpts.Platforms = withoutPlatform(appOpts.Platforms, id)
+				return nil
+			}, opts.Publish)
+		}
+		if err != nil {
+			log.Printf("Error detaching Platform %d from %s application %d: %v", id, usage.Protocol, usage.AppId, err)
+			return report, err
+		}
+		usage.Detached = true
+	}
+	if err := s.Delete(id); err != nil {
+		return report, err
+	}
+	report.Deleted = true
+	return report, nil
+}
+
+func withoutPlatform(refs []PlatformRef, platformId int) []PlatformRef {
+	var result []PlatformRef
+	for _, current := range refs {
+		if current.Id != platformId {
+			result = append(result, current)
+		}
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type safeDeleteServer struct {
+	deleted    bool
+	dnsApps    *fakeDNSAppServer
+	httpUpdate *HTTPAppOpts
+}
+
+func setupSafeDelete(t *testing.T, dnsApps string) (func(), *safeDeleteServer) {
+	teardown := setup()
+	state := &safeDeleteServer{dnsApps: handleDNSApps(t, dnsApps)}
+	mux.HandleFunc("/v2/config/applications/http.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[{"id":7,"name":"redirector","type":"ROUND_ROBIN","protocol":"http","platforms":[{"id":12,"cname":"foo.com"},{"id":34,"cname":"bar.com"}]}]`)
+	})
+	mux.HandleFunc("/v2/config/applications/http.json/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == "PUT" {
+			var opts HTTPAppOpts
+			json.NewDecoder(r.Body).Decode(&opts)
+			state.httpUpdate = &opts
+		}
+		fmt.Fprint(w, `{"id":7,"name":"redirector","type":"ROUND_ROBIN","protocol":"http","platforms":[{"id":12,"cname":"foo.com"},{"id":34,"cname":"bar.com"}]}`)
+	})
+	mux.HandleFunc("/v2/config/platforms.json/12", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Unexpected %s request", r.Method)
+		}
+		state.deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return teardown, state
+}
+
+const safeDeleteDNSApps = `[
+	{"id":1,"name":"foo","type":"RT_HTTP_PERFORMANCE","protocol":"dns","version":1,"platforms":[{"id":12,"cname":"foo.com"},{"id":34,"cname":"bar.com"}]},
+	{"id":2,"name":"bar","type":"ROUND_ROBIN","protocol":"dns","version":1,"platforms":[{"id":34,"cname":"bar.com"}]}
+]`
+
+func TestPlatformSafeDeleteInUse(t *testing.T) {
+	teardown, state := setupSafeDelete(t, safeDeleteDNSApps)
+	defer teardown()
+	report, err := client.Platform.SafeDelete(12, SafeDeleteOptions{})
+	inUse, ok := err.(*PlatformInUseError)
+	if !ok {
+		t.Fatalf("Expected *PlatformInUseError, got %v", err)
+	}
+	expected := []PlatformUsage{
+		{AppId: 1, AppName: "foo", Protocol: ProtocolDNS},
+		{AppId: 7, AppName: "redirector", Protocol: ProtocolHTTP},
+	}
+	if !reflect.DeepEqual(expected, report.Usages) {
+		t.Error(unexpectedValueString("usages", expected, report.Usages))
+	}
+	if err := testValues("error", "Platform 12 is used by dns application 1 (foo), http application 7 (redirector)", inUse.Error()); err != nil {
+		t.Error(err)
+	}
+	if state.deleted || report.Deleted {
+		t.Error("Did not expect the platform to be deleted")
+	}
+}
+
+func TestPlatformSafeDeleteDryRun(t *testing.T) {
+	teardown, state := setupSafeDelete(t, safeDeleteDNSApps)
+	defer teardown()
+	report, err := client.Platform.SafeDelete(12, SafeDeleteOptions{Detach: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("usages", 2, len(report.Usages)); err != nil {
+		t.Error(err)
+	}
+	if state.deleted || len(state.dnsApps.updates) != 0 || state.httpUpdate != nil {
+		t.Error("Did not expect any change")
+	}
+}
+
+func TestPlatformSafeDeleteDetach(t *testing.T) {
+	teardown, state := setupSafeDelete(t, safeDeleteDNSApps)
+	defer teardown()
+	report, err := client.Platform.SafeDelete(12, SafeDeleteOptions{Detach: true, Publish: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Deleted || !state.deleted {
+		t.Error("Expected the platform to be deleted")
+	}
+	for _, current := range report.Usages {
+		if !current.Detached {
+			t.Errorf("Expected the platform to be detached from application %d", current.AppId)
+		}
+	}
+	updates := state.dnsApps.updatesOf(1)
+	if len(updates) != 1 {
+		t.Fatalf("Expected a single update of application 1, got %d", len(updates))
+	}
+	if err := testValues("dns platforms", "[34]", fmt.Sprint(platformRefIds(updates[0].opts.Platforms))); err != nil {
+		t.Error(err)
+	}
+	if len(state.dnsApps.updatesOf(2)) != 0 {
+		t.Error("Did not expect application 2 to be updated")
+	}
+	if err := testValues("http platforms", "[34]", fmt.Sprint(platformRefIds(state.httpUpdate.Platforms))); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPlatformSafeDeleteLastPlatform(t *testing.T) {
+	teardown, state := setupSafeDelete(t, `[{"id":3,"name":"only","type":"ROUND_ROBIN","protocol":"dns","platforms":[{"id":12,"cname":"foo.com"}]}]`)
+	defer teardown()
+	_, err := client.Platform.SafeDelete(12, SafeDeleteOptions{Detach: true, Publish: true})
+	inUse, ok := err.(*PlatformInUseError)
+	if !ok {
+		t.Fatalf("Expected *PlatformInUseError, got %v", err)
+	}
+	if err := testValues("reason", "it is the only platform of only", inUse.Reason); err != nil {
+		t.Error(err)
+	}
+	if state.deleted || len(state.dnsApps.updates) != 0 || state.httpUpdate != nil {
+		t.Error("Did not expect any change")
+	}
+}
+
+func TestPlatformSafeDeleteDetachRequiresPublish(t *testing.T) {
+	teardown, state := setupSafeDelete(t, safeDeleteDNSApps)
+	defer teardown()
+	_, err := client.Platform.SafeDelete(12, SafeDeleteOptions{Detach: true})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	if state.deleted || len(state.dnsApps.updates) != 0 || state.httpUpdate != nil {
+		t.Error("Did not expect any change")
+	}
+}
+
+func TestPlatformSafeDeleteListError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/v2/config/platforms.json/12", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected %s request", r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	report, err := client.Platform.SafeDelete(12, SafeDeleteOptions{})
+	if _, ok := err.(*UnexpectedHTTPStatusError); !ok {
+		t.Fatalf("Expected *UnexpectedHTTPStatusError, got %v", err)
+	}
+	if report != nil {
+		t.Error("Expected nil report")
+	}
+}
@@ -0,0 +1,235 @@
+package itm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+)
+
+// MaintenanceMode is how a Platform is taken out of the Openmix
+// Applications referencing it
+type MaintenanceMode string
+
+const (
+	// MaintenanceDisable disables the Platform within each application
+	MaintenanceDisable MaintenanceMode = "disable"
+	// MaintenanceZeroWeight sets the weight of the Platform to zero within
+	// each application, keeping it enabled
+	MaintenanceZeroWeight MaintenanceMode = "zeroWeight"
+)
+
+// IsValid reports whether m is a known maintenance mode
+func (m MaintenanceMode) IsValid() bool {
+	return m == MaintenanceDisable || m == MaintenanceZeroWeight
+}
+
+// MaintenanceOptions controls how Platform.EnterMaintenance takes a Platform
+// out of service
+type MaintenanceOptions struct {
+	// Mode defaults to MaintenanceDisable
+	Mode MaintenanceMode
+	// Draft saves the changes as drafts instead of publishing them. Live
+	// traffic keeps going to the Platform until the drafts are published.
+	Draft bool
+}
+
+// MaintenanceEntry records the settings a Platform had within an Openmix
+// Application before maintenance
+type MaintenanceEntry struct {
+	AppId   int    `json:"appId"`
+	AppName string `json:"appName"`
+	Weight  *int   `json:"weight,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+	// Restored is set once the settings have been put back
+	Restored bool `json:"restored,omitempty"`
+	// Drifted is set when the settings changed during the maintenance, in
+	// which case they are not restored
+	Drifted bool `json:"drifted,omitempty"`
+}
+
+// drifted reports whether ref no longer holds the value set by mode
+func (e *MaintenanceEntry) drifted(ref *PlatformRef, mode MaintenanceMode) bool {
+	if ref == nil {
+		return true
+	}
+	if mode == MaintenanceZeroWeight {
+		return ref.Weight == nil || *ref.Weight != 0
+	}
+	return ref.IsEnabled()
+}
+
+// MaintenanceToken records everything Platform.EnterMaintenance changed, so
+// that Platform.RestoreMaintenance can undo it. It is meant to be saved, for
+// instance with WriteFile, until the maintenance is over.
+type MaintenanceToken struct {
+	PlatformId int             `json:"platformId"`
+	Mode       MaintenanceMode `json:"mode"`
+	Started    time.Time       `json:"started"`
+	// Draft is set when the changes were saved as drafts; they are restored
+	// the same way
+	Draft   bool               `json:"draft,omitempty"`
+	Entries []MaintenanceEntry `json:"entries"`
+}
+
+// MaintenanceDriftError is returned by Platform.RestoreMaintenance when the
+// settings of the Platform changed in some applications during the
+// maintenance. Those applications are left as they are.
+type MaintenanceDriftError struct {
+	PlatformId int
+	AppIds     []int
+}
+
+func (e MaintenanceDriftError) Error() string {
+	return fmt.Sprintf("Platform %d was changed during maintenance in Openmix Applications %s; not restored", e.PlatformId, joinIds(e.AppIds))
+}
+
+// errMaintenanceDrift stops the restoration of an application that drifted
+var errMaintenanceDrift = errors.New("maintenance settings drifted")
+
+// Write writes the token as JSON
+func (t *MaintenanceToken) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
+
+// WriteFile saves the token to a file, replacing it if it exists
+func (t *MaintenanceToken) WriteFile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := t.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadMaintenanceToken reads a token written by MaintenanceToken.Write
+func ReadMaintenanceToken(r io.Reader) (*MaintenanceToken, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var result MaintenanceToken
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if result.PlatformId <= 0 {
+		return nil, fmt.Errorf("Invalid maintenance token: missing Platform ID")
+	}
+	return &result, nil
+}
+
+// ReadMaintenanceTokenFile reads a token saved by MaintenanceToken.WriteFile
+func ReadMaintenanceTokenFile(filename string) (*MaintenanceToken, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadMaintenanceToken(file)
+}
+
+// EnterMaintenance takes a Platform out of every DNS Openmix Application
+// referencing it, by disabling it or setting its weight to zero. The changes
+// are published unless opts.Draft is set, and a failure to list the
+// applications is returned as an error. The returned token records the
+// previous settings; it is returned along with any error, so the
+// applications already modified can be restored.
+func (s *platformServiceImpl) EnterMaintenance(id int, opts MaintenanceOptions) (*MaintenanceToken, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = MaintenanceDisable
+	}
+	if !mode.IsValid() {
+		return nil, newValidationError([]FieldError{{"mode", "must be one of disable, zeroWeight"}})
+	}
+	apps, err := s.client.DNSApps.List(DNSAppUsesPlatform(id))
+	if err != nil {
+		return nil, err
+	}
+	token := &MaintenanceToken{PlatformId: id, Mode: mode, Started: time.Now().UTC(), Draft: opts.Draft}
+	for _, current := range apps {
+		var entry MaintenanceEntry
+		_, err := s.client.DNSApps.Modify(current.Id, func(appOpts *DNSAppOpts) error {
+			ref := findPlatformRef(appOpts.Platforms, id)
+			if ref == nil {
+				return fmt.Errorf("Platform %d is no longer used by Openmix Application %d", id, current.Id)
+			}
+			saved := ref.copy()
+			entry = MaintenanceEntry{AppId: current.Id, AppName: current.Name, Weight: saved.Weight, Enabled: saved.Enabled}
+			if mode == MaintenanceDisable {
+				disabled := false
+				ref.Enabled = &disabled
+			} else {
+				zero := 0
+				ref.Weight = &zero
+			}
+			return nil
+		}, !opts.Draft)
+		if err != nil {
+			log.Printf("Error putting Platform %d in maintenance in Openmix Application %d: %v", id, current.Id, err)
+			return token, err
+		}
+		token.Entries = append(token.Entries, entry)
+	}
+	return token, nil
+}
+
+// RestoreMaintenance puts back the settings recorded in token, publishing
+// them unless the maintenance was saved as drafts. Entries are marked as
+// restored as they are processed, so that a token can be retried after an
+// error. Applications in which the Platform no longer has the maintenance
+// setting, or no longer appears, were changed by someone else in the
+// meantime: they are left alone, marked as drifted and reported with a
+// *MaintenanceDriftError.
+func (s *platformServiceImpl) RestoreMaintenance(token *MaintenanceToken) error {
+	var drifted []int
+	for i := range token.Entries {
+		entry := &token.Entries[i]
+		if entry.Restored {
+			continue
+		}
+		_, err := s.client.DNSApps.Modify(entry.AppId, func(appOpts *DNSAppOpts) error {
+			ref := findPlatformRef(appOpts.Platforms, token.PlatformId)
+			if entry.drifted(ref, token.Mode) {
+				return errMaintenanceDrift
+			}
+			restored := PlatformRef{Weight: entry.Weight, Enabled: entry.Enabled}.copy()
+			ref.Weight, ref.Enabled = restored.Weight, restored.Enabled
+			return nil
+		}, !token.Draft)
+		if err == errMaintenanceDrift {
+			log.Printf("Platform %d was changed during maintenance in Openmix Application %d; skipping", token.PlatformId, entry.AppId)
+			entry.Drifted = true
+			drifted = append(drifted, entry.AppId)
+			continue
+		}
+		if err != nil {
+			log.Printf("Error restoring Platform %d in Openmix Application %d: %v", token.PlatformId, entry.AppId, err)
+			return err
+		}
+		entry.Restored = true
+	}
+	if len(drifted) > 0 {
+		return &MaintenanceDriftError{PlatformId: token.PlatformId, AppIds: drifted}
+	}
+	return nil
+}
+
+// findPlatformRef returns the reference to a Platform, or nil
+func findPlatformRef(refs []PlatformRef, platformId int) *PlatformRef {
+	for i := range refs {
+		if refs[i].Id == platformId {
+			return &refs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package itm
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const maintenanceDNSApps = `[
+	{"id":1,"name":"foo","type":"ROUND_ROBIN","protocol":"dns","version":1,"platforms":[{"id":12,"cname":"foo.com","weight":60},{"id":34,"cname":"bar.com","weight":40}]},
+	{"id":2,"name":"bar","type":"RT_HTTP_PERFORMANCE","protocol":"dns","version":1,"platforms":[{"id":12,"cname":"foo.com","enabled":true}]},
+	{"id":3,"name":"baz","type":"RT_HTTP_PERFORMANCE","protocol":"dns","version":1,"platforms":[{"id":34,"cname":"bar.com"}]}
+]`
+
+func setupMaintenance(t *testing.T) (func(), *fakeDNSAppServer) {
+	teardown := setup()
+	return teardown, handleDNSApps(t, maintenanceDNSApps)
+}
+
+// publishFlags returns the publish parameter of each update
+func publishFlags(updates []fakeDNSAppUpdate) []string {
+	var result []string
+	for _, current := range updates {
+		result = append(result, current.publish)
+	}
+	return result
+}
+
+func TestPlatformMaintenanceDisable(t *testing.T) {
+	teardown, dnsApps := setupMaintenance(t)
+	defer teardown()
+	token, err := client.Platform.EnterMaintenance(12, MaintenanceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	weight, enabled := 60, true
+	expected := []MaintenanceEntry{
+		{AppId: 1, AppName: "foo", Weight: &weight},
+		{AppId: 2, AppName: "bar", Enabled: &enabled},
+	}
+	if !reflect.DeepEqual(expected, token.Entries) {
+		t.Error(unexpectedValueString("entries", expected, token.Entries))
+	}
+	if err := testValues("mode", MaintenanceDisable, token.Mode); err != nil {
+		t.Error(err)
+	}
+	for _, id := range []int{1, 2} {
+		if ref := findPlatformRef(dnsApps.app(id).Platforms, 12); ref.IsEnabled() {
+			t.Errorf("Expected platform 12 to be disabled in app %d", id)
+		}
+	}
+	if err := testValues("weight", 60, *findPlatformRef(dnsApps.app(1).Platforms, 12).Weight); err != nil {
+		t.Error(err)
+	}
+	if err := client.Platform.RestoreMaintenance(token); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"true", "true", "true", "true"}, publishFlags(dnsApps.updates)) {
+		t.Error(unexpectedValueString("publish", []string{"true", "true", "true", "true"}, publishFlags(dnsApps.updates)))
+	}
+	if ref := findPlatformRef(dnsApps.app(1).Platforms, 12); ref.Enabled != nil || *ref.Weight != 60 {
+		t.Errorf("Expected app 1 to be restored, got %+v", ref)
+	}
+	if ref := findPlatformRef(dnsApps.app(2).Platforms, 12); ref.Enabled == nil || !*ref.Enabled {
+		t.Errorf("Expected app 2 to be restored, got %+v", ref)
+	}
+	for _, current := range token.Entries {
+		if !current.Restored {
+			t.Errorf("Expected app %d to be marked as restored", current.AppId)
+		}
+	}
+}
+
+func TestPlatformMaintenanceZeroWeightTokenFile(t *testing.T) {
+	teardown, dnsApps := setupMaintenance(t)
+	defer teardown()
+	token, err := client.Platform.EnterMaintenance(34, MaintenanceOptions{Mode: MaintenanceZeroWeight, Draft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref := findPlatformRef(dnsApps.app(1).Platforms, 34); !ref.IsEnabled() || *ref.Weight != 0 {
+		t.Errorf("Expected platform 34 to have a zero weight, got %+v", ref)
+	}
+	dir, err := ioutil.TempDir("", "maintenance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "token.json")
+	if err := token.WriteFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadMaintenanceTokenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(token.Entries, loaded.Entries) || !token.Started.Equal(loaded.Started) {
+		t.Error(unexpectedValueString("token", token, loaded))
+	}
+	if err := client.Platform.RestoreMaintenance(loaded); err != nil {
+		t.Fatal(err)
+	}
+	for _, current := range publishFlags(dnsApps.updates) {
+		if current != "false" {
+			t.Errorf("Expected drafts only, got publish=%s", current)
+		}
+	}
+	if ref := findPlatformRef(dnsApps.app(1).Platforms, 34); *ref.Weight != 40 {
+		t.Errorf("Expected app 1 to be restored, got %+v", ref)
+	}
+	if ref := findPlatformRef(dnsApps.app(3).Platforms, 34); ref.Weight != nil {
+		t.Errorf("Expected app 3 to be restored without a weight, got %+v", ref)
+	}
+}
+
+func TestPlatformMaintenanceInvalidMode(t *testing.T) {
+	_, err := client.Platform.EnterMaintenance(12, MaintenanceOptions{Mode: "drain"})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	dir, err := ioutil.TempDir("", "maintenance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "token.json")
+	ioutil.WriteFile(filename, []byte(`{"entries":[]}`), 0600)
+	if _, err := ReadMaintenanceTokenFile(filename); err == nil {
+		t.Error("Expected an error reading a token without a Platform ID")
+	}
+}
+
+func TestPlatformMaintenanceRestoreDrift(t *testing.T) {
+	teardown, dnsApps := setupMaintenance(t)
+	defer teardown()
+	token, err := client.Platform.EnterMaintenance(12, MaintenanceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Someone enables the platform again in app 2 during the maintenance
+	enabled := true
+	findPlatformRef(dnsApps.app(2).Platforms, 12).Enabled = &enabled
+	findPlatformRef(dnsApps.app(2).Platforms, 12).Ttl = 30
+	err = client.Platform.RestoreMaintenance(token)
+	driftErr, ok := err.(*MaintenanceDriftError)
+	if !ok {
+		t.Fatalf("Expected *MaintenanceDriftError, got %v", err)
+	}
+	if err := testValues("error", "Platform 12 was changed during maintenance in Openmix Applications 2; not restored", driftErr.Error()); err != nil {
+		t.Error(err)
+	}
+	if !token.Entries[0].Restored || token.Entries[1].Restored || !token.Entries[1].Drifted {
+		t.Errorf("Expected app 1 to be restored and app 2 to be drifted, got %+v", token.Entries)
+	}
+	if err := testValues("ttl", 30, findPlatformRef(dnsApps.app(2).Platforms, 12).Ttl); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPlatformMaintenanceListError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/config/applications/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	token, err := client.Platform.EnterMaintenance(12, MaintenanceOptions{})
+	if _, ok := err.(*UnexpectedHTTPStatusError); !ok {
+		t.Fatalf("Expected *UnexpectedHTTPStatusError, got %v", err)
+	}
+	if token != nil {
+		t.Error("Expected nil token")
+	}
+}
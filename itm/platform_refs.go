@@ -0,0 +1,119 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PlatformRef references a Platform from an Openmix Application, along with
+// the settings that apply to that Platform within the application.
+type PlatformRef struct {
+	Id    int    `json:"id"`
+	Cname string `json:"cname"`
+	// Weight is only sent when set, as zero is a meaningful weight
+	Weight *int `json:"weight,omitempty"`
+	// Enabled is only sent when set; a nil value means enabled
+	Enabled *bool `json:"enabled,omitempty"`
+	Ttl     int   `json:"ttl,omitempty"`
+
+	// Extra holds fields the SDK does not model, kept so that updating the
+	// Openmix Application does not drop them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *PlatformRef) UnmarshalJSON(data []byte) error {
+	type plain PlatformRef
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = PlatformRef(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v PlatformRef) MarshalJSON() ([]byte, error) {
+	type plain PlatformRef
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// NewPlatformRef creates and returns a new PlatformRef routing to cname
+func NewPlatformRef(id int, cname string) PlatformRef {
+	return PlatformRef{
+		Id:    id,
+		Cname: cname,
+	}
+}
+
+// IsEnabled reports whether the Platform is enabled within the application
+func (r *PlatformRef) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// Validate checks the settings of the reference
+func (r *PlatformRef) Validate() error {
+	return newValidationError(r.fieldErrors("platform"))
+}
+
+func (r *PlatformRef) fieldErrors(prefix string) []FieldError {
+	var result []FieldError
+	if r.Id <= 0 {
+		result = append(result, FieldError{prefix + ".id", "must be a positive Platform ID"})
+	}
+	if r.Cname == "" {
+		result = append(result, FieldError{prefix + ".cname", "is required"})
+	}
+	if r.Weight != nil && *r.Weight < 0 {
+		result = append(result, FieldError{prefix + ".weight", "must not be negative"})
+	}
+	if r.Ttl < 0 {
+		result = append(result, FieldError{prefix + ".ttl", "must not be negative"})
+	}
+	return result
+}
+
+// copy returns a deep copy of the reference
+func (r PlatformRef) copy() PlatformRef {
+	if r.Weight != nil {
+		weight := *r.Weight
+		r.Weight = &weight
+	}
+	if r.Enabled != nil {
+		enabled := *r.Enabled
+		r.Enabled = &enabled
+	}
+	r.Extra = copyExtra(r.Extra)
+	return r
+}
+
+func copyPlatformRefs(refs []PlatformRef) []PlatformRef {
+	var result []PlatformRef
+	for _, current := range refs {
+		result = append(result, current.copy())
+	}
+	return result
+}
+
+// ValidatePlatformRefs checks every reference in refs. A Platform may only be
+// referenced once.
+func ValidatePlatformRefs(refs []PlatformRef) error {
+	return newValidationError(platformRefsFieldErrors(refs))
+}
+
+func platformRefsFieldErrors(refs []PlatformRef) []FieldError {
+	var result []FieldError
+	seen := make(map[int]bool)
+	for index, current := range refs {
+		prefix := fmt.Sprintf("platforms[%d]", index)
+		result = append(result, current.fieldErrors(prefix)...)
+		if seen[current.Id] {
+			result = append(result, FieldError{prefix + ".id", fmt.Sprintf("Platform %d is referenced more than once", current.Id)})
+		}
+		seen[current.Id] = true
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package itm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPlatformRefJSON(t *testing.T) {
+	weight := 0
+	enabled := false
+	testData := []struct {
+		ref      PlatformRef
+		expected string
+	}{
+		{
+			NewPlatformRef(12, "foo.com"),
+			`{"id":12,"cname":"foo.com"}`,
+		},
+		{
+			PlatformRef{Id: 12, Cname: "foo.com", Weight: &weight, Enabled: &enabled, Ttl: 30},
+			`{"id":12,"cname":"foo.com","weight":0,"enabled":false,"ttl":30}`,
+		},
+		{
+			PlatformRef{Id: 12, Cname: "foo.com", Extra: map[string]json.RawMessage{"sonarThreshold": json.RawMessage("90")}},
+			`{"cname":"foo.com","id":12,"sonarThreshold":90}`,
+		},
+	}
+	for _, current := range testData {
+		data, err := json.Marshal(current.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testValues("platform ref JSON", current.expected, string(data)); err != nil {
+			t.Error(err)
+		}
+		var decoded PlatformRef
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(current.ref, decoded) {
+			t.Error(unexpectedValueString("decoded platform ref", current.ref, decoded))
+		}
+	}
+}
+
+func TestPlatformRefIsEnabled(t *testing.T) {
+	enabled := false
+	ref := NewPlatformRef(12, "foo.com")
+	if !ref.IsEnabled() {
+		t.Error("Expected a reference without enabled flag to be enabled")
+	}
+	ref.Enabled = &enabled
+	if ref.IsEnabled() {
+		t.Error("Expected a disabled reference")
+	}
+}
+
+func TestValidatePlatformRefs(t *testing.T) {
+	weight := -1
+	refs := []PlatformRef{
+		NewPlatformRef(12, "foo.com"),
+		{Id: 0, Cname: "", Weight: &weight},
+		NewPlatformRef(12, "bar.com"),
+	}
+	err := ValidatePlatformRefs(refs)
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError; got %v", err)
+	}
+	expected := []FieldError{
+		{"platforms[1].id", "must be a positive Platform ID"},
+		{"platforms[1].cname", "is required"},
+		{"platforms[1].weight", "must not be negative"},
+		{"platforms[2].id", "Platform 12 is referenced more than once"},
+	}
+	if !reflect.DeepEqual(expected, validationErr.Fields) {
+		t.Error(unexpectedValueString("field errors", expected, validationErr.Fields))
+	}
+	if err := ValidatePlatformRefs(refs[:1]); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
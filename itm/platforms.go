@@ -4,39 +4,125 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"reflect"
 )
 
 const platformBasePath = "v2/config/platforms.json"
 
 // PlatformOpts specifies settings used to create a new Citrix ITM Platform
 type PlatformOpts struct {
-	Name                      string                 `json:"name"`
-	DisplayName               string                 `json:"displayName"`
-	Category                  map[string]interface{} `json:"category"`
-	RadarOpts                 map[string]interface{} `json:"radarConfig"`
-	SonarOpts                 map[string]interface{} `json:"sonarConfig"`
-	Description               string                 `json:"intendedUse"`
-	Enabled                   bool                   `json:"enabled"`
-	OpenMixEnabled            bool                   `json:"openmixEnabled"`
-	IsPrivate                 bool                   `json:"privateArchetype"`
-	OpenmixVisible            bool                   `json:"openmixVisible"`
-	PublicProviderArchetypeId int                    `json:"publicProviderArchetypeId"`
+	Name                      string            `json:"name"`
+	DisplayName               string            `json:"displayName"`
+	Category                  *PlatformCategory `json:"category,omitempty"`
+	RadarOpts                 *RadarConfig      `json:"radarConfig,omitempty"`
+	SonarOpts                 *SonarConfig      `json:"sonarConfig,omitempty"`
+	Description               string            `json:"intendedUse"`
+	Enabled                   bool              `json:"enabled"`
+	OpenMixEnabled            bool              `json:"openmixEnabled"`
+	IsPrivate                 bool              `json:"privateArchetype"`
+	OpenmixVisible            bool              `json:"openmixVisible"`
+	PublicProviderArchetypeId int               `json:"publicProviderArchetypeId"`
+	// Extra holds settings the SDK does not model. They are sent as is.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *PlatformOpts) UnmarshalJSON(data []byte) error {
+	type plain PlatformOpts
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = PlatformOpts(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v PlatformOpts) MarshalJSON() ([]byte, error) {
+	type plain PlatformOpts
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// Validate checks the settings of the Platform, including its Radar and
+// Sonar configuration. The returned error is a *ValidationError listing
+// every invalid field.
+func (o *PlatformOpts) Validate() error {
+	var fields []FieldError
+	if o.Name == "" {
+		fields = append(fields, FieldError{"name", "is required"})
+	}
+	if o.Category == nil || o.Category.Id <= 0 {
+		fields = append(fields, FieldError{"category.id", "is required"})
+	}
+	if o.RadarOpts != nil {
+		fields = append(fields, o.RadarOpts.fieldErrors("radarConfig")...)
+	}
+	if o.SonarOpts != nil {
+		fields = append(fields, o.SonarOpts.fieldErrors("sonarConfig")...)
+	}
+	return newValidationError(fields)
 }
 
 // Platform species settings of an existing Citrix ITM Platform
 type Platform struct {
-	Id                        int                    `json:"id"`
-	Name                      string                 `json:"name"`
-	DisplayName               string                 `json:"displayName"`
-	Category                  map[string]interface{} `json:"category"`
-	RadarOpts                 map[string]interface{} `json:"radarConfig"`
-	SonarOpts                 map[string]interface{} `json:"sonarConfig"`
-	Description               string                 `json:"intendedUse"`
-	Enabled                   bool                   `json:"enabled"`
-	OpenMixEnabled            bool                   `bool:"openmixEnabled"`
-	IsPrivate                 bool                   `json:"privateArchetype"`
-	OpenmixVisible            bool                   `json:"openmixVisible"`
-	PublicProviderArchetypeId int                    `json:"publicProviderArchetypeId"`
+	Id                        int               `json:"id"`
+	Name                      string            `json:"name"`
+	DisplayName               string            `json:"displayName"`
+	Category                  *PlatformCategory `json:"category,omitempty"`
+	RadarOpts                 *RadarConfig      `json:"radarConfig,omitempty"`
+	SonarOpts                 *SonarConfig      `json:"sonarConfig,omitempty"`
+	Description               string            `json:"intendedUse"`
+	Enabled                   bool              `json:"enabled"`
+	OpenMixEnabled            bool              `json:"openmixEnabled"`
+	IsPrivate                 bool              `json:"privateArchetype"`
+	OpenmixVisible            bool              `json:"openmixVisible"`
+	PublicProviderArchetypeId int               `json:"publicProviderArchetypeId"`
+	// Extra holds fields returned by the API that the SDK does not model.
+	// ToOpts carries them over, so that an update keeps them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the fields the SDK does
+// not model in Extra.
+func (v *Platform) UnmarshalJSON(data []byte) error {
+	type plain Platform
+	var result plain
+	extra, err := unmarshalWithExtra(data, &result)
+	if err != nil {
+		return err
+	}
+	*v = Platform(result)
+	v.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, sending back the fields kept in Extra.
+func (v Platform) MarshalJSON() ([]byte, error) {
+	type plain Platform
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+// ToOpts returns the settings of the Platform as a PlatformOpts struct,
+// suitable for passing to Update. Fields PlatformOpts does not model are
+// carried over in Extra.
+func (p *Platform) ToOpts() PlatformOpts {
+	return PlatformOpts{
+		Name:                      p.Name,
+		DisplayName:               p.DisplayName,
+		Category:                  p.Category.copy(),
+		RadarOpts:                 p.RadarOpts.copy(),
+		SonarOpts:                 p.SonarOpts.copy(),
+		Description:               p.Description,
+		Enabled:                   p.Enabled,
+		OpenMixEnabled:            p.OpenMixEnabled,
+		IsPrivate:                 p.IsPrivate,
+		OpenmixVisible:            p.OpenmixVisible,
+		PublicProviderArchetypeId: p.PublicProviderArchetypeId,
+		Extra:                     optsExtra(p, PlatformOpts{}, "id"),
+	}
 }
 
 type platformListTestFunc func(*Platform) bool
@@ -47,6 +133,11 @@ type platformService interface {
 	Get(int) (*Platform, error)
 	Delete(int) error
 	List(opts ...platformListTestFunc) ([]Platform, error)
+	Modify(int, func(*PlatformOpts) error) (*Platform, error)
+	UpdateIfChanged(int, *PlatformOpts) (*Platform, bool, error)
+	SafeDelete(int, SafeDeleteOptions) (*PlatformDeleteReport, error)
+	EnterMaintenance(int, MaintenanceOptions) (*MaintenanceToken, error)
+	RestoreMaintenance(*MaintenanceToken) error
 }
 
 type platformServiceImpl struct {
@@ -118,13 +209,16 @@ func (s *platformServiceImpl) Get(id int) (*Platform, error) {
 // Delete a Platform using Platform ID
 func (s *platformServiceImpl) Delete(id int) error {
 	resp, err := s.client.delete(getPlatformPath(id))
+	if err != nil {
+		return err
+	}
 	if 204 != resp.StatusCode {
 		return &UnexpectedHTTPStatusError{
 			Expected: 204,
 			Got:      resp.StatusCode,
 		}
 	}
-	return err
+	return nil
 }
 
 // Gives the list of existing Platform
@@ -151,6 +245,60 @@ func (s *platformServiceImpl) List(tests ...platformListTestFunc) ([]Platform, e
 	return result, nil
 }
 
+// Modify fetches a Platform, applies mutate to its settings and writes the
+// result back, provided the Platform has not changed on the server in the
+// meantime. Platforms carry no version, so the check compares the whole
+// object. The cycle is retried a few times before giving up with a
+// ConflictError. The check is best effort: the API has no conditional update,
+// so a write by another client between the check and the update is lost.
+func (s *platformServiceImpl) Modify(id int, mutate func(*PlatformOpts) error) (*Platform, error) {
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		current, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		opts := current.ToOpts()
+		if err := mutate(&opts); err != nil {
+			return nil, err
+		}
+		latest, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, latest) {
+			log.Printf("Platform %d changed while being modified; retrying", id)
+			continue
+		}
+		return s.Update(id, &opts)
+	}
+	return nil, &ConflictError{
+		Resource: "Platform",
+		Id:       id,
+		Attempts: maxModifyAttempts,
+	}
+}
+
+// UpdateIfChanged updates a Platform only when opts differs from the live
+// Platform. The returned bool reports whether an update was sent.
+func (s *platformServiceImpl) UpdateIfChanged(id int, opts *PlatformOpts) (*Platform, bool, error) {
+	current, err := s.Get(id)
+	if err != nil {
+		return nil, false, err
+	}
+	diffs, err := current.Diff(opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(diffs) == 0 {
+		return current, false, nil
+	}
+	result, err := s.Update(id, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
 // Get Platform APIs URL
 func getPlatformPath(id int) string {
 	return fmt.Sprintf("%s/%d", platformBasePath, id)
@@ -9,9 +9,9 @@ import (
 )
 
 func TestErrorIssuingPostOnCreatePlatform(t *testing.T) {
-	category := map[string]interface{}{"id": float64(1)}
-	radar := map[string]interface{}{"usePublicData": true}
-	sonar := map[string]interface{}{"enabled": false}
+	category := &PlatformCategory{Id: 1}
+	radar := &RadarConfig{UsePublicData: true}
+	sonar := &SonarConfig{Enabled: false}
 	fakeClient := newFakeHTTPClient(
 		fakeRoundTripper{
 			resp: nil,
@@ -39,9 +39,9 @@ func TestErrorIssuingPostOnCreatePlatform(t *testing.T) {
 }
 
 func TestErrorIssuingPutOnUpdatePlatform(t *testing.T) {
-	category := map[string]interface{}{"id": float64(1)}
-	radar := map[string]interface{}{"usePublicData": true}
-	sonar := map[string]interface{}{"enabled": false}
+	category := &PlatformCategory{Id: 1}
+	radar := &RadarConfig{UsePublicData: true}
+	sonar := &SonarConfig{Enabled: false}
 	fakeClient := newFakeHTTPClient(
 		fakeRoundTripper{
 			resp: nil,
@@ -90,17 +90,17 @@ func TestErrorIssuingGetPlatform(t *testing.T) {
 func TestPlatformCreate(t *testing.T) {
 	teardown := setup()
 	defer teardown()
-	category := map[string]interface{}{"id": float64(1)}
-	radar := map[string]interface{}{"usePublicData": true}
-	sonar := map[string]interface{}{"enabled": false}
+	category := &PlatformCategory{Id: 1}
+	radar := &RadarConfig{UsePublicData: true}
+	sonar := &SonarConfig{Enabled: false}
 	mux.HandleFunc("/v2/config/platforms.json", func(w http.ResponseWriter, r *http.Request) {
 		var parsedBody map[string]interface{}
 		expectedRequestData := map[string]interface{}{
 			"name":        "foo",
 			"displayName": "foo",
-			"category":    category,
-			"radarConfig": radar,
-			"sonarConfig": sonar,
+			"category":    map[string]interface{}{"id": float64(1)},
+			"radarConfig": map[string]interface{}{"usePublicData": true},
+			"sonarConfig": map[string]interface{}{"enabled": false},
 			"intendedUse": "foo description",
 		}
 		responseBodyObj := Platform{
@@ -162,17 +162,17 @@ func TestPlatformCreate(t *testing.T) {
 func TestPlatformUpdate(t *testing.T) {
 	teardown := setup()
 	defer teardown()
-	category := map[string]interface{}{"id": float64(1)}
-	radar := map[string]interface{}{"usePublicData": true}
-	sonar := map[string]interface{}{"enabled": false}
+	category := &PlatformCategory{Id: 1}
+	radar := &RadarConfig{UsePublicData: true}
+	sonar := &SonarConfig{Enabled: false}
 	mux.HandleFunc("/v2/config/platforms.json/123", func(w http.ResponseWriter, r *http.Request) {
 		var parsedBody map[string]interface{}
 		expectedRequestData := map[string]interface{}{
 			"name":        "updated_foo_name",
 			"displayName": "updated_foo_name",
-			"category":    category,
-			"radarConfig": radar,
-			"sonarConfig": sonar,
+			"category":    map[string]interface{}{"id": float64(1)},
+			"radarConfig": map[string]interface{}{"usePublicData": true},
+			"sonarConfig": map[string]interface{}{"enabled": false},
 			"intendedUse": "updated foo description",
 		}
 		responseBodyObj := Platform{
@@ -234,9 +234,9 @@ func TestPlatformUpdate(t *testing.T) {
 func TestPlatformGet(t *testing.T) {
 	teardown := setup()
 	defer teardown()
-	category := map[string]interface{}{"id": float64(1)}
-	radar := map[string]interface{}{"usePublicData": true}
-	sonar := map[string]interface{}{"enabled": false}
+	category := &PlatformCategory{Id: 1}
+	radar := &RadarConfig{UsePublicData: true}
+	sonar := &SonarConfig{Enabled: false}
 	mux.HandleFunc("/v2/config/platforms.json/123", func(w http.ResponseWriter, r *http.Request) {
 		responseBodyObj := Platform{
 			Id:          123,
@@ -294,9 +294,9 @@ func TestPlatformDelete(t *testing.T) {
 func TestPlatformList(t *testing.T) {
 	teardown := setup()
 	defer teardown()
-	category := map[string]interface{}{"id": float64(1)}
-	radar := map[string]interface{}{"usePublicData": true}
-	sonar := map[string]interface{}{"enabled": false}
+	category := &PlatformCategory{Id: 1}
+	radar := &RadarConfig{UsePublicData: true}
+	sonar := &SonarConfig{Enabled: false}
 	var plaforms []Platform
 	platform1 := Platform{
 		Id:          123,
@@ -336,3 +336,43 @@ func TestPlatformList(t *testing.T) {
 		}
 	}
 }
+
+func TestPlatformModify(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	category := &PlatformCategory{Id: 1}
+	platform := Platform{
+		Id:          123,
+		Name:        "foo",
+		DisplayName: "foo",
+		Category:    category,
+		Description: "foo description",
+		Enabled:     true,
+	}
+	mux.HandleFunc("/v2/config/platforms.json/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			var parsedBody PlatformOpts
+			if err := json.NewDecoder(r.Body).Decode(&parsedBody); err != nil {
+				t.Fatalf("JSON decoding error: %v", err)
+			}
+			if err := testValues("display name", "foo", parsedBody.DisplayName); err != nil {
+				t.Error(err)
+			}
+			platform.Enabled = parsedBody.Enabled
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		responseBody, _ := json.Marshal(platform)
+		fmt.Fprint(w, string(responseBody))
+	})
+	result, err := client.Platform.Modify(123, func(opts *PlatformOpts) error {
+		opts.Enabled = false
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("enabled", false, result.Enabled); err != nil {
+		t.Error(err)
+	}
+}
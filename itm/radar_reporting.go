@@ -0,0 +1,186 @@
+package itm
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const radarReportingPath = "v2/reporting/radar.json"
+
+// RadarGrouping is the dimension Radar measurements are aggregated by
+type RadarGrouping string
+
+const (
+	// RadarByCountry groups measurements by ISO country code
+	RadarByCountry RadarGrouping = "country"
+	// RadarByRegion groups measurements by world region
+	RadarByRegion RadarGrouping = "region"
+	// RadarByASN groups measurements by the autonomous system of the users
+	RadarByASN RadarGrouping = "asn"
+)
+
+// RadarGroupings lists the supported groupings
+var RadarGroupings = []RadarGrouping{RadarByCountry, RadarByRegion, RadarByASN}
+
+// IsValid reports whether g is a known grouping
+func (g RadarGrouping) IsValid() bool {
+	for _, current := range RadarGroupings {
+		if g == current {
+			return true
+		}
+	}
+	return false
+}
+
+// RadarQuery selects the Radar measurements to report on
+type RadarQuery struct {
+	// PlatformIds restricts the report to these platforms; every platform
+	// is reported on when empty
+	PlatformIds []int
+	GroupBy     RadarGrouping
+	Start       time.Time
+	End         time.Time
+}
+
+// Validate checks the query. The returned error is a *ValidationError.
+func (q *RadarQuery) Validate() error {
+	fields := timeRangeFieldErrors(q.Start, q.End)
+	if !q.GroupBy.IsValid() {
+		fields = append(fields, FieldError{"groupBy", "must be one of country, region, asn"})
+	}
+	return newValidationError(fields)
+}
+
+func (q *RadarQuery) values() url.Values {
+	qs := url.Values{}
+	setTimeRange(qs, q.Start, q.End)
+	qs.Set("groupBy", string(q.GroupBy))
+	setIds(qs, "platformIds", q.PlatformIds)
+	return qs
+}
+
+// Percentiles summarizes the distribution of a measurement
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// RadarRow holds the Radar measurements of a Platform for one location,
+// which is a country code, a region or an ASN depending on the grouping
+type RadarRow struct {
+	PlatformId   int    `json:"platformId"`
+	Location     string `json:"location"`
+	Measurements int    `json:"measurements"`
+	// RTT is in milliseconds
+	RTT Percentiles `json:"rtt"`
+	// Throughput is in kilobits per second
+	Throughput Percentiles `json:"throughput"`
+	// Availability is a percentage
+	Availability float64 `json:"availability"`
+}
+
+// RadarReport is the result of a RadarQuery
+type RadarReport struct {
+	Query RadarQuery
+	Rows  []RadarRow
+}
+
+// ForPlatform returns the rows of a Platform
+func (r *RadarReport) ForPlatform(platformId int) []RadarRow {
+	var result []RadarRow
+	for _, current := range r.Rows {
+		if current.PlatformId == platformId {
+			result = append(result, current)
+		}
+	}
+	return result
+}
+
+// MeasurementTable converts the report into the input of SimulateDNSApp,
+// using median RTT and throughput
+func (r *RadarReport) MeasurementTable() MeasurementTable {
+	var result MeasurementTable
+	for _, current := range r.Rows {
+		result = append(result, Measurement{
+			PlatformId:   current.PlatformId,
+			Location:     current.Location,
+			RTT:          current.RTT.P50,
+			Throughput:   current.Throughput.P50,
+			Availability: current.Availability,
+		})
+	}
+	return result
+}
+
+// radarCSVHeader names the columns written by WriteCSV
+var radarCSVHeader = []string{
+	"platform_id", "location", "measurements",
+	"rtt_p50", "rtt_p75", "rtt_p90", "rtt_p95", "rtt_p99",
+	"throughput_p50", "throughput_p75", "throughput_p90", "throughput_p95", "throughput_p99",
+	"availability",
+}
+
+// WriteCSV writes the rows of the report as CSV, with a header line
+func (r *RadarReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(radarCSVHeader); err != nil {
+		return err
+	}
+	for _, current := range r.Rows {
+		record := []string{strconv.Itoa(current.PlatformId), current.Location, strconv.Itoa(current.Measurements)}
+		record = append(record, current.RTT.strings()...)
+		record = append(record, current.Throughput.strings()...)
+		record = append(record, formatFloat(current.Availability))
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the rows of the report as an indented JSON array
+func (r *RadarReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	rows := r.Rows
+	if rows == nil {
+		rows = []RadarRow{}
+	}
+	return encoder.Encode(rows)
+}
+
+func (p Percentiles) strings() []string {
+	return []string{formatFloat(p.P50), formatFloat(p.P75), formatFloat(p.P90), formatFloat(p.P95), formatFloat(p.P99)}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+type radarService interface {
+	Report(*RadarQuery) (*RadarReport, error)
+}
+
+type radarServiceImpl struct {
+	client *Client
+}
+
+// Report returns the Radar measurements selected by query
+func (s *radarServiceImpl) Report(query *RadarQuery) (*RadarReport, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	result := &RadarReport{Query: *query}
+	if err := s.client.getReport(radarReportingPath, query.values(), &result.Rows); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package itm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var radarTestStart = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func setupRadarReport(t *testing.T) func() {
+	teardown := setup()
+	mux.HandleFunc("/v2/reporting/radar.json", func(w http.ResponseWriter, r *http.Request) {
+		expected := fmt.Sprintf("end=%d&groupBy=country&platformIds=12%%2C34&start=%d", radarTestStart.AddDate(0, 0, 7).Unix(), radarTestStart.Unix())
+		if err := testValues("query", expected, r.URL.RawQuery); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[
+			{"platformId":12,"location":"FR","measurements":1500,"rtt":{"p50":42,"p75":55,"p90":80,"p95":120,"p99":300},"throughput":{"p50":9000.5,"p75":7000,"p90":5000,"p95":3000,"p99":1000},"availability":99.2},
+			{"platformId":34,"location":"FR","measurements":800,"rtt":{"p50":38,"p75":50,"p90":70,"p95":90,"p99":200},"throughput":{"p50":8000},"availability":97}
+		]`)
+	})
+	return teardown
+}
+
+func radarTestQuery() *RadarQuery {
+	return &RadarQuery{
+		PlatformIds: []int{12, 34},
+		GroupBy:     RadarByCountry,
+		Start:       radarTestStart,
+		End:         radarTestStart.AddDate(0, 0, 7),
+	}
+}
+
+func TestRadarReport(t *testing.T) {
+	teardown := setupRadarReport(t)
+	defer teardown()
+	report, err := client.Radar.Report(radarTestQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("rows", 2, len(report.Rows)); err != nil {
+		t.Fatal(err)
+	}
+	expected := Percentiles{P50: 42, P75: 55, P90: 80, P95: 120, P99: 300}
+	if !reflect.DeepEqual(expected, report.Rows[0].RTT) {
+		t.Error(unexpectedValueString("rtt", expected, report.Rows[0].RTT))
+	}
+	if err := testValues("platform rows", 1, len(report.ForPlatform(34))); err != nil {
+		t.Error(err)
+	}
+	table := report.MeasurementTable()
+	expectedTable := MeasurementTable{
+		{PlatformId: 12, Location: "FR", RTT: 42, Throughput: 9000.5, Availability: 99.2},
+		{PlatformId: 34, Location: "FR", RTT: 38, Throughput: 8000, Availability: 97},
+	}
+	if !reflect.DeepEqual(expectedTable, table) {
+		t.Error(unexpectedValueString("measurement table", expectedTable, table))
+	}
+}
+
+func TestRadarReportExport(t *testing.T) {
+	teardown := setupRadarReport(t)
+	defer teardown()
+	report, err := client.Radar.Report(radarTestQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var csvOutput bytes.Buffer
+	if err := report.WriteCSV(&csvOutput); err != nil {
+		t.Fatal(err)
+	}
+	expectedCSV := "platform_id,location,measurements,rtt_p50,rtt_p75,rtt_p90,rtt_p95,rtt_p99,throughput_p50,throughput_p75,throughput_p90,throughput_p95,throughput_p99,availability\n" +
+		"12,FR,1500,42,55,80,120,300,9000.5,7000,5000,3000,1000,99.2\n" +
+		"34,FR,800,38,50,70,90,200,8000,0,0,0,0,97\n"
+	if err := testValues("csv", expectedCSV, csvOutput.String()); err != nil {
+		t.Error(err)
+	}
+	var jsonOutput bytes.Buffer
+	if err := report.WriteJSON(&jsonOutput); err != nil {
+		t.Fatal(err)
+	}
+	var rows []RadarRow
+	if err := json.Unmarshal(jsonOutput.Bytes(), &rows); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Rows, rows) {
+		t.Error(unexpectedValueString("json rows", report.Rows, rows))
+	}
+	var empty bytes.Buffer
+	(&RadarReport{}).WriteJSON(&empty)
+	if err := testValues("empty json", "[]\n", empty.String()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRadarQueryValidation(t *testing.T) {
+	query := &RadarQuery{GroupBy: "city", Start: radarTestStart, End: radarTestStart}
+	_, err := client.Radar.Report(query)
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	expected := []FieldError{
+		{"end", "must be after start"},
+		{"groupBy", "must be one of country, region, asn"},
+	}
+	if !reflect.DeepEqual(expected, validationErr.Fields) {
+		t.Error(unexpectedValueString("field errors", expected, validationErr.Fields))
+	}
+}
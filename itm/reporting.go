@@ -0,0 +1,173 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeRangeFieldErrors checks the time range of a reporting query
+func timeRangeFieldErrors(start time.Time, end time.Time) []FieldError {
+	var result []FieldError
+	if start.IsZero() {
+		result = append(result, FieldError{"start", "is required"})
+	}
+	if end.IsZero() {
+		result = append(result, FieldError{"end", "is required"})
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		result = append(result, FieldError{"end", "must be after start"})
+	}
+	return result
+}
+
+// setTimeRange adds the time range of a reporting query to qs, as Unix times
+func setTimeRange(qs url.Values, start time.Time, end time.Time) {
+	qs.Set("start", strconv.FormatInt(start.Unix(), 10))
+	qs.Set("end", strconv.FormatInt(end.Unix(), 10))
+}
+
+// setIds adds a comma separated list of IDs to qs, when there are any
+func setIds(qs url.Values, key string, ids []int) {
+	if len(ids) == 0 {
+		return
+	}
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+	qs.Set(key, strings.Join(values, ","))
+}
+
+// getReport fetches a report and decodes it into result
+func (c *Client) getReport(path string, qs url.Values, result interface{}) error {
+	resp, err := c.get(fmt.Sprintf("%s?%s", path, qs.Encode()))
+	if err != nil {
+		log.Printf("Error issuing get request for report %s: %v", path, err)
+		return err
+	}
+	if 200 != resp.StatusCode {
+		log.Printf("UnexpectedHTTPStatusError details: %s", string(resp.Body))
+		return &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	return json.Unmarshal(resp.Body, result)
+}
+
+// ReportInterval is the size of the time buckets of a report
+type ReportInterval string
+
+// Report intervals
+const (
+	Interval5Minutes ReportInterval = "5m"
+	IntervalHour     ReportInterval = "1h"
+	IntervalDay      ReportInterval = "1d"
+)
+
+// ReportIntervals lists the supported intervals
+var ReportIntervals = []ReportInterval{Interval5Minutes, IntervalHour, IntervalDay}
+
+// IsValid reports whether i is a known interval
+func (i ReportInterval) IsValid() bool {
+	for _, current := range ReportIntervals {
+		if i == current {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultReportPageSize applies to paginated reports when the query sets no
+// page size
+const defaultReportPageSize = 1000
+
+// setPage adds the page number and page size of a paginated report to qs.
+// Pages are numbered from 1.
+func setPage(qs url.Values, page int, pageSize int) {
+	if pageSize <= 0 {
+		pageSize = defaultReportPageSize
+	}
+	qs.Set("page", strconv.Itoa(page))
+	qs.Set("pageSize", strconv.Itoa(pageSize))
+}
+
+// getPages fetches every page of a paginated report. fetch is called with
+// each page number, from 1, and returns the total number of pages.
+func getPages(fetch func(page int) (int, error)) error {
+	for page := 1; ; page++ {
+		totalPages, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		if page >= totalPages {
+			return nil
+		}
+	}
+}
+
+// ReportTotal is the count of a time bucket of a report
+type ReportTotal struct {
+	Time  time.Time
+	Count int64
+}
+
+// countedRows gives access to the time bucket and count of each row of a
+// report, so that the aggregation helpers can be shared between reports
+type countedRows interface {
+	Len() int
+	At(i int) (time.Time, int64)
+}
+
+// sumCounts returns the total count of rows
+func sumCounts(rows countedRows) int64 {
+	var result int64
+	for i := 0; i < rows.Len(); i++ {
+		_, count := rows.At(i)
+		result += count
+	}
+	return result
+}
+
+// sumCountsBy sums the counts of rows by the key returned for each row index
+func sumCountsBy(rows countedRows, key func(i int) string) map[string]int64 {
+	result := make(map[string]int64)
+	for i := 0; i < rows.Len(); i++ {
+		_, count := rows.At(i)
+		result[key(i)] += count
+	}
+	return result
+}
+
+// sumCountsById sums the counts of rows by the ID returned for each row index
+func sumCountsById(rows countedRows, id func(i int) int) map[int]int64 {
+	result := make(map[int]int64)
+	for i := 0; i < rows.Len(); i++ {
+		_, count := rows.At(i)
+		result[id(i)] += count
+	}
+	return result
+}
+
+// timeline sums the counts of rows by time bucket, oldest first
+func timeline(rows countedRows) []ReportTotal {
+	totals := make(map[time.Time]int64)
+	for i := 0; i < rows.Len(); i++ {
+		bucket, count := rows.At(i)
+		totals[bucket.UTC()] += count
+	}
+	var result []ReportTotal
+	for bucket, count := range totals {
+		result = append(result, ReportTotal{Time: bucket, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
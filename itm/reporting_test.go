@@ -0,0 +1,94 @@
+package itm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// handleReportPages serves pages of a paginated report at path, checking the
+// query parameters shared by every page
+func handleReportPages(t *testing.T, path string, expected map[string]string, pages []string) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		for key, value := range expected {
+			if err := testValues(key, value, query.Get(key)); err != nil {
+				t.Error(err)
+			}
+		}
+		var page int
+		fmt.Sscan(query.Get("page"), &page)
+		if page < 1 || page > len(pages) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, pages[page-1])
+	})
+}
+
+func TestGetPages(t *testing.T) {
+	var fetched []int
+	err := getPages(func(page int) (int, error) {
+		fetched = append(fetched, page)
+		return 3, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]int{1, 2, 3}, fetched) {
+		t.Error(unexpectedValueString("pages", []int{1, 2, 3}, fetched))
+	}
+	failure := errors.New("failure")
+	err = getPages(func(page int) (int, error) {
+		if page == 2 {
+			return 0, failure
+		}
+		return 3, nil
+	})
+	if err != failure {
+		t.Errorf("Expected the error of the failing page, got %v", err)
+	}
+}
+
+type testCountedRows []ReportTotal
+
+func (r testCountedRows) Len() int {
+	return len(r)
+}
+
+func (r testCountedRows) At(i int) (time.Time, int64) {
+	return r[i].Time, r[i].Count
+}
+
+func TestReportAggregation(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rows := testCountedRows{
+		{Time: start.Add(time.Hour), Count: 5},
+		{Time: start.In(time.FixedZone("CET", 3600)), Count: 2},
+		{Time: start, Count: 3},
+	}
+	if err := testValues("total", int64(10), sumCounts(rows)); err != nil {
+		t.Error(err)
+	}
+	byHour := sumCountsById(rows, func(i int) int {
+		return rows[i].Time.UTC().Hour()
+	})
+	if !reflect.DeepEqual(map[int]int64{0: 5, 1: 5}, byHour) {
+		t.Error(unexpectedValueString("by hour", map[int]int64{0: 5, 1: 5}, byHour))
+	}
+	expected := []ReportTotal{
+		{Time: start, Count: 5},
+		{Time: start.Add(time.Hour), Count: 5},
+	}
+	if !reflect.DeepEqual(expected, timeline(rows)) {
+		t.Error(unexpectedValueString("timeline", expected, timeline(rows)))
+	}
+	if timeline(testCountedRows{}) != nil {
+		t.Error("Expected no timeline without rows")
+	}
+}
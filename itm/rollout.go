@@ -0,0 +1,247 @@
+package itm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// RolloutStep is one stage of a progressive traffic shift
+type RolloutStep struct {
+	// Weights maps Platform IDs to the weight they get at this step. The
+	// platforms not listed keep their weight.
+	Weights map[int]int
+	// Hold is how long the weights are left in place before the health gate
+	// is checked
+	Hold time.Duration
+}
+
+// HealthGate decides whether a rollout may proceed after a step. A non-nil
+// error stops the rollout and reverts the weights.
+type HealthGate func(step *RolloutStep) error
+
+// RolloutOptions describes a progressive traffic shift within an Openmix
+// Application
+type RolloutOptions struct {
+	Steps []RolloutStep
+	// Gate is checked after every step; no check is made when nil
+	Gate HealthGate
+	// Draft saves the steps as drafts instead of publishing them. Drafts do
+	// not move live traffic, so a Gate cannot be used along with Draft.
+	Draft bool
+	// Wait is used to hold each step. It defaults to waiting for the
+	// duration, or until ctx is done, in which case it returns ctx.Err().
+	Wait func(ctx context.Context, d time.Duration) error
+}
+
+// Validate checks the options. The returned error is a *ValidationError.
+func (o *RolloutOptions) Validate() error {
+	var fields []FieldError
+	if len(o.Steps) == 0 {
+		fields = append(fields, FieldError{"steps", "at least one step is required"})
+	}
+	if o.Draft && o.Gate != nil {
+		fields = append(fields, FieldError{"draft", "cannot be used with a health gate, as drafts do not move live traffic"})
+	}
+	for index, current := range o.Steps {
+		prefix := fmt.Sprintf("steps[%d]", index)
+		if len(current.Weights) == 0 {
+			fields = append(fields, FieldError{prefix + ".weights", "is required"})
+		}
+		for _, platformId := range sortedPlatformIds(current.Weights) {
+			if current.Weights[platformId] < 0 {
+				fields = append(fields, FieldError{fmt.Sprintf("%s.weights[%d]", prefix, platformId), "must not be negative"})
+			}
+		}
+		if current.Hold < 0 {
+			fields = append(fields, FieldError{prefix + ".hold", "must not be negative"})
+		}
+	}
+	return newValidationError(fields)
+}
+
+// ShiftSteps builds the steps moving traffic from one Platform to another.
+// Each percentage is the share of the traffic sent to the target Platform
+// at that step; a final step at 100 is added when missing.
+func ShiftSteps(from int, to int, percentages []int, hold time.Duration) []RolloutStep {
+	var result []RolloutStep
+	for _, current := range percentages {
+		result = append(result, RolloutStep{
+			Weights: map[int]int{from: 100 - current, to: current},
+			Hold:    hold,
+		})
+	}
+	if len(percentages) == 0 || percentages[len(percentages)-1] != 100 {
+		result = append(result, RolloutStep{
+			Weights: map[int]int{from: 0, to: 100},
+			Hold:    hold,
+		})
+	}
+	return result
+}
+
+// SonarHealthGate returns a HealthGate failing when Sonar reports one of the
+// platforms as unavailable. When no platform is given, the platforms with a
+// positive weight in the step are checked.
+func SonarHealthGate(client *Client, platformIds ...int) HealthGate {
+	return func(step *RolloutStep) error {
+		ids := platformIds
+		if len(ids) == 0 {
+			for _, platformId := range sortedPlatformIds(step.Weights) {
+				if step.Weights[platformId] > 0 {
+					ids = append(ids, platformId)
+				}
+			}
+		}
+		for _, platformId := range ids {
+			status, err := client.Sonar.Status(platformId)
+			if err != nil {
+				return err
+			}
+			if !status.Available {
+				return fmt.Errorf("Sonar reports Platform %d as unavailable: %s", platformId, status.Message)
+			}
+		}
+		return nil
+	}
+}
+
+// RolloutResult describes what a rollout did
+type RolloutResult struct {
+	AppId int
+	// Completed is the number of steps that passed their health gate
+	Completed int
+	// Original holds the platforms of the application before the rollout
+	Original []PlatformRef
+	Reverted bool
+}
+
+// RolloutError is returned when a rollout stops before its last step
+type RolloutError struct {
+	AppId int
+	// Step is the index of the failed step
+	Step  int
+	Cause error
+	// Reverted reports whether the original weights were put back
+	Reverted bool
+	// RevertErr is set when putting back the original weights failed
+	RevertErr error
+}
+
+func (e RolloutError) Error() string {
+	message := fmt.Sprintf("Rollout of Openmix Application %d failed at step %d: %v", e.AppId, e.Step+1, e.Cause)
+	if e.RevertErr != nil {
+		message += fmt.Sprintf("; reverting failed: %v", e.RevertErr)
+	} else if e.Reverted {
+		message += "; original weights restored"
+	}
+	return message
+}
+
+// Rollout adjusts the weights of the platforms of an Openmix Application
+// step by step, publishing each step, holding it and checking opts.Gate
+// before moving on. When a step cannot be applied, its health gate fails or
+// ctx is cancelled, the original weights are put back and a *RolloutError is
+// returned.
+func (s *dnsAppsServiceImpl) Rollout(ctx context.Context, id int, opts RolloutOptions) (*RolloutResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	wait := opts.Wait
+	if wait == nil {
+		wait = waitContext
+	}
+	app, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	result := &RolloutResult{AppId: id, Original: copyPlatformRefs(app.Platforms)}
+	for index, current := range opts.Steps {
+		for _, platformId := range sortedPlatformIds(current.Weights) {
+			if findPlatformRef(app.Platforms, platformId) == nil {
+				return nil, newValidationError([]FieldError{{
+					fmt.Sprintf("steps[%d].weights[%d]", index, platformId),
+					fmt.Sprintf("Platform %d is not used by Openmix Application %d", platformId, id),
+				}})
+			}
+		}
+	}
+	for index := range opts.Steps {
+		step := &opts.Steps[index]
+		err := ctx.Err()
+		if err == nil {
+			err = s.applyStep(id, step, !opts.Draft)
+		}
+		if err == nil {
+			err = wait(ctx, step.Hold)
+		}
+		if err == nil && opts.Gate != nil {
+			err = opts.Gate(step)
+		}
+		if err != nil {
+			log.Printf("Rollout of Openmix Application %d failed at step %d: %v", id, index+1, err)
+			rolloutErr := &RolloutError{AppId: id, Step: index, Cause: err}
+			rolloutErr.RevertErr = s.revertWeights(id, result.Original, !opts.Draft)
+			rolloutErr.Reverted = rolloutErr.RevertErr == nil
+			result.Reverted = rolloutErr.Reverted
+			return result, rolloutErr
+		}
+		result.Completed++
+	}
+	return result, nil
+}
+
+// applyStep sets the weights of a rollout step
+func (s *dnsAppsServiceImpl) applyStep(id int, step *RolloutStep, publish bool) error {
+	_, err := s.Modify(id, func(appOpts *DNSAppOpts) error {
+		for platformId, weight := range step.Weights {
+			ref := findPlatformRef(appOpts.Platforms, platformId)
+			if ref == nil {
+				return fmt.Errorf("Platform %d is no longer used by Openmix Application %d", platformId, id)
+			}
+			value := weight
+			ref.Weight = &value
+		}
+		return nil
+	}, publish)
+	return err
+}
+
+// waitContext waits for d, or until ctx is done
+func waitContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// revertWeights puts back the weights of original
+func (s *dnsAppsServiceImpl) revertWeights(id int, original []PlatformRef, publish bool) error {
+	_, err := s.Modify(id, func(appOpts *DNSAppOpts) error {
+		for _, current := range original {
+			if ref := findPlatformRef(appOpts.Platforms, current.Id); ref != nil {
+				ref.Weight = current.copy().Weight
+			}
+		}
+		return nil
+	}, publish)
+	if err != nil {
+		log.Printf("Error reverting the weights of Openmix Application %d: %v", id, err)
+	}
+	return err
+}
+
+func sortedPlatformIds(weights map[int]int) []int {
+	var result []int
+	for platformId := range weights {
+		result = append(result, platformId)
+	}
+	sort.Ints(result)
+	return result
+}
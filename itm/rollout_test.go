@@ -0,0 +1,185 @@
+package itm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setupRollout serves a DNS application that keeps the settings it is
+// updated with, and records the weights of platforms 12 and 34 after each
+// update
+func setupRollout(t *testing.T) (func(), *[][2]int) {
+	teardown := setup()
+	dnsApps := handleDNSApps(t, `[{"id":1,"name":"foo","type":"ROUND_ROBIN","protocol":"dns","version":1,
+		"platforms":[{"id":12,"cname":"foo.com","weight":100},{"id":34,"cname":"bar.com","weight":0},{"id":56,"cname":"baz.com"}]}]`)
+	var history [][2]int
+	dnsApps.onUpdate = func(app *DNSApp, r *http.Request) {
+		history = append(history, [2]int{*findPlatformRef(app.Platforms, 12).Weight, *findPlatformRef(app.Platforms, 34).Weight})
+		if err := testValues("publish", "true", r.URL.Query().Get("publish")); err != nil {
+			t.Error(err)
+		}
+		if findPlatformRef(app.Platforms, 56).Weight != nil {
+			t.Error("Did not expect platform 56 to get a weight")
+		}
+	}
+	return teardown, &history
+}
+
+func TestRollout(t *testing.T) {
+	teardown, history := setupRollout(t)
+	defer teardown()
+	var waited []time.Duration
+	var gated int
+	result, err := client.DNSApps.Rollout(context.Background(), 1, RolloutOptions{
+		Steps: ShiftSteps(12, 34, []int{10, 50}, time.Minute),
+		Gate: func(step *RolloutStep) error {
+			gated++
+			return nil
+		},
+		Wait: func(ctx context.Context, d time.Duration) error {
+			waited = append(waited, d)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][2]int{{90, 10}, {50, 50}, {0, 100}}
+	if !reflect.DeepEqual(expected, *history) {
+		t.Error(unexpectedValueString("weights", expected, *history))
+	}
+	if err := testValues("completed", 3, result.Completed); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("gated", 3, gated); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual([]time.Duration{time.Minute, time.Minute, time.Minute}, waited) {
+		t.Error(unexpectedValueString("waits", []time.Duration{time.Minute, time.Minute, time.Minute}, waited))
+	}
+	if result.Reverted {
+		t.Error("Did not expect the rollout to be reverted")
+	}
+}
+
+func TestRolloutRevertsOnGateFailure(t *testing.T) {
+	teardown, history := setupRollout(t)
+	defer teardown()
+	mux.HandleFunc("/v2/reporting/sonar/status.json/12", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"platformId":12,"available":true}`)
+	})
+	mux.HandleFunc("/v2/reporting/sonar/status.json/34", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		available := len(*history) < 2
+		fmt.Fprintf(w, `{"platformId":34,"available":%t,"message":"timeout"}`, available)
+	})
+	result, err := client.DNSApps.Rollout(context.Background(), 1, RolloutOptions{
+		Steps: ShiftSteps(12, 34, []int{25, 50}, 0),
+		Gate:  SonarHealthGate(client),
+	})
+	rolloutErr, ok := err.(*RolloutError)
+	if !ok {
+		t.Fatalf("Expected *RolloutError, got %v", err)
+	}
+	if err := testValues("step", 1, rolloutErr.Step); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("error", "Rollout of Openmix Application 1 failed at step 2: Sonar reports Platform 34 as unavailable: timeout; original weights restored", rolloutErr.Error()); err != nil {
+		t.Error(err)
+	}
+	expected := [][2]int{{75, 25}, {50, 50}, {100, 0}}
+	if !reflect.DeepEqual(expected, *history) {
+		t.Error(unexpectedValueString("weights", expected, *history))
+	}
+	if err := testValues("completed", 1, result.Completed); err != nil {
+		t.Error(err)
+	}
+	if !result.Reverted {
+		t.Error("Expected the rollout to be reverted")
+	}
+}
+
+func TestRolloutValidation(t *testing.T) {
+	teardown, history := setupRollout(t)
+	defer teardown()
+	_, err := client.DNSApps.Rollout(context.Background(), 1, RolloutOptions{
+		Steps: []RolloutStep{{}, {Weights: map[int]int{12: -1}, Hold: -time.Second}},
+		Gate:  SonarHealthGate(client),
+		Draft: true,
+	})
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	expected := []FieldError{
+		{"draft", "cannot be used with a health gate, as drafts do not move live traffic"},
+		{"steps[0].weights", "is required"},
+		{"steps[1].weights[12]", "must not be negative"},
+		{"steps[1].hold", "must not be negative"},
+	}
+	if !reflect.DeepEqual(expected, validationErr.Fields) {
+		t.Error(unexpectedValueString("field errors", expected, validationErr.Fields))
+	}
+	_, err = client.DNSApps.Rollout(context.Background(), 1, RolloutOptions{
+		Steps: ShiftSteps(12, 78, nil, 0),
+		Gate: func(*RolloutStep) error {
+			return errors.New("unexpected gate check")
+		},
+	})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("Expected *ValidationError, got %v", err)
+	}
+	if len(*history) != 0 {
+		t.Error("Did not expect the application to be updated")
+	}
+}
+
+func TestRolloutCancel(t *testing.T) {
+	teardown, history := setupRollout(t)
+	defer teardown()
+	ctx, cancel := context.WithCancel(context.Background())
+	holding := make(chan struct{})
+	done := make(chan struct{})
+	var result *RolloutResult
+	var err error
+	go func() {
+		defer close(done)
+		result, err = client.DNSApps.Rollout(ctx, 1, RolloutOptions{
+			Steps: ShiftSteps(12, 34, []int{10, 50}, time.Hour),
+			Wait: func(ctx context.Context, d time.Duration) error {
+				close(holding)
+				return waitContext(ctx, d)
+			},
+		})
+	}()
+	// The first step is held for an hour; cancelling must not wait for it
+	<-holding
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Rollout did not stop after cancellation")
+	}
+	rolloutErr, ok := err.(*RolloutError)
+	if !ok {
+		t.Fatalf("Expected *RolloutError, got %v", err)
+	}
+	if err := testValues("cause", context.Canceled, rolloutErr.Cause); err != nil {
+		t.Error(err)
+	}
+	expected := [][2]int{{90, 10}, {100, 0}}
+	if !reflect.DeepEqual(expected, *history) {
+		t.Error(unexpectedValueString("weights", expected, *history))
+	}
+	if !result.Reverted || result.Completed != 0 {
+		t.Errorf("Expected the rollout to be reverted before completing a step, got %+v", result)
+	}
+}
@@ -0,0 +1,199 @@
+package itm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	sonarStatusBasePath  = "v2/reporting/sonar/status.json"
+	sonarHistoryBasePath = "v2/reporting/sonar/history.json"
+)
+
+// SonarStatus is the current Sonar availability of a Platform
+type SonarStatus struct {
+	PlatformId int       `json:"platformId"`
+	Available  bool      `json:"available"`
+	LastCheck  time.Time `json:"lastCheck"`
+	// StatusCode is the HTTP status of the last check, zero for TCP checks
+	StatusCode int    `json:"statusCode,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// SonarCheck is the result of a single Sonar health check
+type SonarCheck struct {
+	Time       time.Time `json:"timestamp"`
+	Available  bool      `json:"available"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	// ResponseTime is in milliseconds
+	ResponseTime float64 `json:"responseTime,omitempty"`
+	Message      string  `json:"message,omitempty"`
+}
+
+// SonarOutage is a period during which the checks of a Platform failed. End
+// is the time of the first successful check that followed, or the zero time
+// when the Platform was still down at the end of the history.
+type SonarOutage struct {
+	Start time.Time
+	End   time.Time
+	// Checks is the number of failed checks in the outage
+	Checks int
+}
+
+// Duration returns the length of the outage. An outage that has not ended
+// is measured up to until.
+func (o *SonarOutage) Duration(until time.Time) time.Duration {
+	if o.End.IsZero() {
+		return until.Sub(o.Start)
+	}
+	return o.End.Sub(o.Start)
+}
+
+// SonarHistory holds the Sonar checks of a Platform over a time window,
+// oldest first
+type SonarHistory struct {
+	PlatformId int
+	Start      time.Time
+	End        time.Time
+	Checks     []SonarCheck
+}
+
+// Uptime returns the percentage of successful checks, or zero when there are
+// no checks
+func (h *SonarHistory) Uptime() float64 {
+	return sonarUptime(h.Checks)
+}
+
+// UptimeBetween works like Uptime, only counting the checks made from start
+// (included) to end (excluded)
+func (h *SonarHistory) UptimeBetween(start time.Time, end time.Time) float64 {
+	var checks []SonarCheck
+	for _, current := range h.Checks {
+		if !current.Time.Before(start) && current.Time.Before(end) {
+			checks = append(checks, current)
+		}
+	}
+	return sonarUptime(checks)
+}
+
+// Outages returns the periods of consecutive failed checks
+func (h *SonarHistory) Outages() []SonarOutage {
+	var result []SonarOutage
+	var current *SonarOutage
+	for _, check := range h.Checks {
+		if !check.Available {
+			if current == nil {
+				current = &SonarOutage{Start: check.Time}
+			}
+			current.Checks++
+			continue
+		}
+		if current != nil {
+			current.End = check.Time
+			result = append(result, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		result = append(result, *current)
+	}
+	return result
+}
+
+func sonarUptime(checks []SonarCheck) float64 {
+	if len(checks) == 0 {
+		return 0
+	}
+	available := 0
+	for _, current := range checks {
+		if current.Available {
+			available++
+		}
+	}
+	return 100 * float64(available) / float64(len(checks))
+}
+
+type sonarService interface {
+	Statuses() ([]SonarStatus, error)
+	Status(int) (*SonarStatus, error)
+	History(int, time.Time, time.Time) (*SonarHistory, error)
+}
+
+type sonarServiceImpl struct {
+	client *Client
+}
+
+// Statuses returns the current Sonar availability of every Platform with
+// Sonar enabled
+func (s *sonarServiceImpl) Statuses() ([]SonarStatus, error) {
+	resp, err := s.client.get(sonarStatusBasePath)
+	if err != nil {
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	var result []SonarStatus
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Status returns the current Sonar availability of a Platform
+func (s *sonarServiceImpl) Status(platformId int) (*SonarStatus, error) {
+	resp, err := s.client.get(fmt.Sprintf("%s/%d", sonarStatusBasePath, platformId))
+	if err != nil {
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	var result SonarStatus
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// History returns the Sonar checks of a Platform made from start to end
+func (s *sonarServiceImpl) History(platformId int, start time.Time, end time.Time) (*SonarHistory, error) {
+	if !start.Before(end) {
+		return nil, fmt.Errorf("Invalid time window: %s is not before %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	qs := url.Values{}
+	qs.Set("start", strconv.FormatInt(start.Unix(), 10))
+	qs.Set("end", strconv.FormatInt(end.Unix(), 10))
+	resp, err := s.client.get(fmt.Sprintf("%s/%d?%s", sonarHistoryBasePath, platformId, qs.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	if 200 != resp.StatusCode {
+		return nil, &UnexpectedHTTPStatusError{
+			Expected: 200,
+			Got:      resp.StatusCode,
+		}
+	}
+	result := &SonarHistory{
+		PlatformId: platformId,
+		Start:      start,
+		End:        end,
+	}
+	if err := json.Unmarshal(resp.Body, &result.Checks); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(result.Checks, func(i, j int) bool {
+		return result.Checks[i].Time.Before(result.Checks[j].Time)
+	})
+	return result, nil
+}
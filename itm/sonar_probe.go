@@ -0,0 +1,160 @@
+package itm
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSonarProbeTimeout applies when the Sonar configuration has no
+	// timeout
+	DefaultSonarProbeTimeout = 10 * time.Second
+	// maxSonarProbeBody limits how much of a response is searched for the
+	// expected content
+	maxSonarProbeBody = 1024 * 1024
+)
+
+// SonarProbe runs locally the health check a SonarConfig describes, so that
+// a configuration can be tried before it is saved with Platform.Update
+type SonarProbe struct {
+	Config *SonarConfig
+	// Address, when set, is the host:port the probe connects to instead of
+	// the host of the URL, for instance to check a server before DNS points
+	// to it. The URL host is still used for the Host header and TLS.
+	Address string
+	// InsecureSkipVerify disables the verification of TLS certificates
+	InsecureSkipVerify bool
+}
+
+// NewSonarProbe returns a probe for config, after checking that config
+// describes a check that can be run
+func NewSonarProbe(config *SonarConfig) (*SonarProbe, error) {
+	fields := config.fieldErrors("sonarConfig")
+	if config.URL == "" && !config.Enabled {
+		fields = append(fields, FieldError{"sonarConfig.url", "is required"})
+	} else if parsed, err := url.Parse(config.URL); err == nil && parsed.Scheme == "tcp" && parsed.Port() == "" {
+		fields = append(fields, FieldError{"sonarConfig.url", "must include a port for TCP checks"})
+	}
+	if err := newValidationError(fields); err != nil {
+		return nil, err
+	}
+	return &SonarProbe{Config: config}, nil
+}
+
+// Run performs the check once. Failures of the checked server are reported
+// in the result rather than as an error: Available is false and Message
+// explains why.
+func (p *SonarProbe) Run() *SonarCheck {
+	start := time.Now()
+	var result *SonarCheck
+	target, err := url.Parse(p.Config.URL)
+	if err != nil {
+		result = &SonarCheck{Message: err.Error()}
+	} else if target.Scheme == "tcp" {
+		result = p.runTCP(target)
+	} else {
+		result = p.runHTTP(target)
+	}
+	result.Time = start
+	result.ResponseTime = float64(time.Since(start)) / float64(time.Millisecond)
+	return result
+}
+
+func (p *SonarProbe) timeout() time.Duration {
+	if p.Config.TimeoutSeconds > 0 {
+		return time.Duration(p.Config.TimeoutSeconds) * time.Second
+	}
+	return DefaultSonarProbeTimeout
+}
+
+func (p *SonarProbe) address(target *url.URL) string {
+	if p.Address != "" {
+		return p.Address
+	}
+	port := target.Port()
+	if port == "" {
+		port = "80"
+		if target.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(target.Hostname(), port)
+}
+
+func (p *SonarProbe) runTCP(target *url.URL) *SonarCheck {
+	conn, err := net.DialTimeout("tcp", p.address(target), p.timeout())
+	if err != nil {
+		return &SonarCheck{Message: err.Error()}
+	}
+	conn.Close()
+	return &SonarCheck{Available: true}
+}
+
+func (p *SonarProbe) runHTTP(target *url.URL) *SonarCheck {
+	address := p.address(target)
+	dialer := &net.Dialer{Timeout: p.timeout()}
+	client := &http.Client{
+		Timeout: p.timeout(),
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: p.InsecureSkipVerify},
+			DisableKeepAlives: true,
+		},
+		// Sonar judges the response it gets, it does not follow redirects
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	method := strings.ToUpper(p.Config.Method)
+	if method == "" {
+		method = "GET"
+	}
+	req, err := http.NewRequest(method, target.String(), nil)
+	if err != nil {
+		return &SonarCheck{Message: err.Error()}
+	}
+	if p.Config.HostHeader != "" {
+		req.Host = p.Config.HostHeader
+	}
+	req.Header.Set("User-Agent", defaultUserAgentString)
+	resp, err := client.Do(req)
+	if err != nil {
+		return &SonarCheck{Message: err.Error()}
+	}
+	defer resp.Body.Close()
+	result := &SonarCheck{StatusCode: resp.StatusCode}
+	if !p.statusMatches(resp.StatusCode) {
+		result.Message = fmt.Sprintf("Unexpected HTTP status %d", resp.StatusCode)
+		return result
+	}
+	if p.Config.ExpectedContent != "" {
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSonarProbeBody))
+		if err != nil {
+			result.Message = err.Error()
+			return result
+		}
+		if !strings.Contains(string(body), p.Config.ExpectedContent) {
+			result.Message = fmt.Sprintf("Response does not contain %q", p.Config.ExpectedContent)
+			return result
+		}
+	}
+	result.Available = true
+	return result
+}
+
+func (p *SonarProbe) statusMatches(status int) bool {
+	if p.Config.ExpectedStatus != 0 {
+		return status == p.Config.ExpectedStatus
+	}
+	return status >= 200 && status < 400
+}
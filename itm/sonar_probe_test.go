@@ -0,0 +1,140 @@
+package itm
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newSonarProbeTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			if r.Host != "www.foo.com" && !strings.HasPrefix(r.Host, "127.0.0.1") {
+				t.Errorf("Unexpected host %s", r.Host)
+			}
+			w.WriteHeader(http.StatusOK)
+			if r.Method != "HEAD" {
+				fmt.Fprintf(w, "status=ok host=%s", r.Host)
+			}
+		case "/moved":
+			http.Redirect(w, r, "/health", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+}
+
+func runSonarProbe(t *testing.T, config *SonarConfig, address string) *SonarCheck {
+	probe, err := NewSonarProbe(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	probe.Address = address
+	return probe.Run()
+}
+
+func TestSonarProbeHTTP(t *testing.T) {
+	server := newSonarProbeTestServer(t)
+	defer server.Close()
+	testData := []struct {
+		name      string
+		config    SonarConfig
+		available bool
+		status    int
+		message   string
+	}{
+		{"healthy", SonarConfig{URL: server.URL + "/health"}, true, 200, ""},
+		{"head", SonarConfig{URL: server.URL + "/health", Method: "head"}, true, 200, ""},
+		{"down", SonarConfig{URL: server.URL + "/down"}, false, 503, "Unexpected HTTP status 503"},
+		{"redirect accepted", SonarConfig{URL: server.URL + "/moved"}, true, 302, ""},
+		{"redirect not expected", SonarConfig{URL: server.URL + "/moved", ExpectedStatus: 200}, false, 302, "Unexpected HTTP status 302"},
+		{"content", SonarConfig{URL: server.URL + "/health", ExpectedContent: "status=ok"}, true, 200, ""},
+		{"missing content", SonarConfig{URL: server.URL + "/health", ExpectedContent: "status=degraded"}, false, 200, `Response does not contain "status=degraded"`},
+		{"host header", SonarConfig{URL: server.URL + "/health", HostHeader: "www.foo.com", ExpectedContent: "host=www.foo.com"}, true, 200, ""},
+	}
+	for _, current := range testData {
+		config := current.config
+		result := runSonarProbe(t, &config, "")
+		got := []interface{}{result.Available, result.StatusCode, result.Message}
+		expected := []interface{}{current.available, current.status, current.message}
+		if !reflect.DeepEqual(expected, got) {
+			t.Error(unexpectedValueString(current.name, expected, got))
+		}
+		if result.Time.IsZero() || result.ResponseTime <= 0 {
+			t.Errorf("%s: expected the check time and response time to be set", current.name)
+		}
+	}
+}
+
+func TestSonarProbeAddressOverride(t *testing.T) {
+	server := newSonarProbeTestServer(t)
+	defer server.Close()
+	config := &SonarConfig{URL: "http://www.foo.com/health", ExpectedContent: "host=www.foo.com"}
+	result := runSonarProbe(t, config, server.Listener.Addr().String())
+	if !result.Available {
+		t.Errorf("Expected the check to succeed: %s", result.Message)
+	}
+}
+
+func TestSonarProbeInvalidURL(t *testing.T) {
+	probe := &SonarProbe{Config: &SonarConfig{URL: "://foo.com"}}
+	result := probe.Run()
+	if result.Available || result.Message == "" {
+		t.Errorf("Expected the check to fail with a message, got %+v", result)
+	}
+}
+
+func TestSonarProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	result := runSonarProbe(t, &SonarConfig{URL: "tcp://" + address, TimeoutSeconds: 1}, "")
+	if !result.Available {
+		t.Errorf("Expected the check to succeed: %s", result.Message)
+	}
+	listener.Close()
+	result = runSonarProbe(t, &SonarConfig{URL: "tcp://" + address, TimeoutSeconds: 1}, "")
+	if result.Available || result.Message == "" {
+		t.Error("Expected the check to fail once the listener is closed")
+	}
+}
+
+func TestNewSonarProbeInvalidConfig(t *testing.T) {
+	testData := []struct {
+		config   SonarConfig
+		expected []FieldError
+	}{
+		{SonarConfig{}, []FieldError{{"sonarConfig.url", "is required"}}},
+		{SonarConfig{Enabled: true}, []FieldError{{"sonarConfig.url", "is required when Sonar is enabled"}}},
+		{SonarConfig{URL: "tcp://foo.com"}, []FieldError{{"sonarConfig.url", "must include a port for TCP checks"}}},
+		{SonarConfig{URL: "http://foo.com", Method: "PATCH"}, []FieldError{{"sonarConfig.method", "must be one of GET, HEAD, POST"}}},
+	}
+	for _, current := range testData {
+		config := current.config
+		_, err := NewSonarProbe(&config)
+		validationErr, ok := err.(*ValidationError)
+		if !ok {
+			t.Errorf("Expected *ValidationError, got %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(current.expected, validationErr.Fields) {
+			t.Error(unexpectedValueString("field errors", current.expected, validationErr.Fields))
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package itm
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSonarStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/v2/reporting/sonar/status.json/12", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"platformId":12,"available":false,"lastCheck":"2026-10-18T10:00:00Z","statusCode":503,"message":"Service Unavailable","location":"eu-west"}`)
+	})
+	mux.HandleFunc("/v2/reporting/sonar/status.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[{"platformId":12,"available":false},{"platformId":34,"available":true}]`)
+	})
+	status, err := client.Sonar.Status(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Available {
+		t.Error("Expected the platform to be down")
+	}
+	if err := testValues("last check", time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC), status.LastCheck); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("status code", 503, status.StatusCode); err != nil {
+		t.Error(err)
+	}
+	statuses, err := client.Sonar.Statuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("status count", 2, len(statuses)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSonarHistory(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	start := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	mux.HandleFunc("/v2/reporting/sonar/history.json/12", func(w http.ResponseWriter, r *http.Request) {
+		if err := testValues("start", fmt.Sprint(start.Unix()), r.URL.Query().Get("start")); err != nil {
+			t.Error(err)
+		}
+		if err := testValues("end", fmt.Sprint(end.Unix()), r.URL.Query().Get("end")); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `[
+			{"timestamp":"2026-10-18T10:05:00Z","available":false,"statusCode":503},
+			{"timestamp":"2026-10-18T10:00:00Z","available":true,"statusCode":200,"responseTime":42.5},
+			{"timestamp":"2026-10-18T10:10:00Z","available":false},
+			{"timestamp":"2026-10-18T10:15:00Z","available":true},
+			{"timestamp":"2026-10-18T10:20:00Z","available":false}
+		]`)
+	})
+	history, err := client.Sonar.History(12, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testValues("first check", start, history.Checks[0].Time); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("uptime", 40.0, history.Uptime()); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("uptime between", 50.0, history.UptimeBetween(start, start.Add(20*time.Minute))); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("empty uptime", 0.0, history.UptimeBetween(end, end.Add(time.Hour))); err != nil {
+		t.Error(err)
+	}
+	outages := history.Outages()
+	expected := []SonarOutage{
+		{Start: start.Add(5 * time.Minute), End: start.Add(15 * time.Minute), Checks: 2},
+		{Start: start.Add(20 * time.Minute), Checks: 1},
+	}
+	if !reflect.DeepEqual(expected, outages) {
+		t.Error(unexpectedValueString("outages", expected, outages))
+	}
+	if err := testValues("duration", 10*time.Minute, outages[0].Duration(end)); err != nil {
+		t.Error(err)
+	}
+	if err := testValues("ongoing duration", 40*time.Minute, outages[1].Duration(end)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSonarHistoryInvalidWindow(t *testing.T) {
+	start := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	if _, err := client.Sonar.History(12, start, start); err == nil {
+		t.Error("Expected an error for an empty time window")
+	}
+}
@@ -0,0 +1,9 @@
+/* Route everything to foo, ignoring "}" in comments */
+function init(config) {
+    config.requireProvider('foo');
+}
+
+function onRequest(request, response) {
+    response.respond('foo', 'foo.com');
+    response.setTTL(20);
+}
@@ -0,0 +1,55 @@
+// Openmix primary/backup failover application generated by citrix-go. Do not edit.
+
+var aliases = [
+    "foo",
+    "bar",
+    "baz"
+];
+var providers = [
+    {
+        "alias": "foo",
+        "cname": "foo.com"
+    },
+    {
+        "alias": "bar",
+        "cname": "bar.com"
+    },
+    {
+        "alias": "baz",
+        "cname": "baz.com"
+    }
+];
+var ttl = 30;
+
+function init(config) {
+    for (var i = 0; i < aliases.length; i++) {
+        config.requireProvider(aliases[i]);
+    }
+}
+
+function isAvailable(sonar, alias) {
+    if (sonar === undefined || sonar[alias] === undefined) {
+        return false;
+    }
+    try {
+        return JSON.parse(sonar[alias]).avail > 0;
+    } catch (e) {
+        return false;
+    }
+}
+
+function onRequest(request, response) {
+    var sonar = request.getData('sonar');
+    var chosen = providers[0];
+    var reason = 'C';
+    for (var i = 0; i < providers.length; i++) {
+        if (isAvailable(sonar, providers[i].alias)) {
+            chosen = providers[i];
+            reason = i === 0 ? 'A' : 'B';
+            break;
+        }
+    }
+    response.respond(chosen.alias, chosen.cname);
+    response.setTTL(ttl);
+    response.setReasonCode(reason);
+}
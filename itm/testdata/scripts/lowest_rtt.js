@@ -0,0 +1,52 @@
+// Openmix lowest round trip time application generated by citrix-go. Do not edit.
+
+var aliases = [
+    "foo",
+    "bar"
+];
+var providers = [
+    {
+        "alias": "foo",
+        "cname": "foo.com"
+    },
+    {
+        "alias": "bar",
+        "cname": "bar.com"
+    }
+];
+var availabilityThreshold = 90;
+var ttl = 20;
+
+function init(config) {
+    for (var i = 0; i < aliases.length; i++) {
+        config.requireProvider(aliases[i]);
+    }
+}
+
+function onRequest(request, response) {
+    var avail = request.getProbe('avail');
+    var rtt = request.getProbe('http_rtt');
+    var chosen;
+    var chosenRtt;
+    for (var i = 0; i < providers.length; i++) {
+        var alias = providers[i].alias;
+        if (avail[alias] === undefined || avail[alias].avail < availabilityThreshold) {
+            continue;
+        }
+        if (rtt[alias] === undefined) {
+            continue;
+        }
+        if (chosen === undefined || rtt[alias].http_rtt < chosenRtt) {
+            chosen = providers[i];
+            chosenRtt = rtt[alias].http_rtt;
+        }
+    }
+    var reason = 'A';
+    if (chosen === undefined) {
+        chosen = providers[0];
+        reason = 'B';
+    }
+    response.respond(chosen.alias, chosen.cname);
+    response.setTTL(ttl);
+    response.setReasonCode(reason);
+}
@@ -0,0 +1,37 @@
+// Openmix static country routing application generated by citrix-go. Do not edit.
+
+var aliases = [
+    "foo",
+    "bar",
+    "baz"
+];
+var cnames = {
+    "bar": "bar.com",
+    "baz": "baz.com",
+    "foo": "foo.com"
+};
+var routes = {
+    "DE": "bar",
+    "FR": "bar",
+    "US": "foo"
+};
+var defaultProvider = "baz";
+var ttl = 60;
+
+function init(config) {
+    for (var i = 0; i < aliases.length; i++) {
+        config.requireProvider(aliases[i]);
+    }
+}
+
+function onRequest(request, response) {
+    var alias = routes[request.country];
+    var reason = 'A';
+    if (alias === undefined) {
+        alias = defaultProvider;
+        reason = 'B';
+    }
+    response.respond(alias, cnames[alias]);
+    response.setTTL(ttl);
+    response.setReasonCode(reason);
+}
@@ -0,0 +1,44 @@
+// Openmix weighted round robin application generated by citrix-go. Do not edit.
+
+var aliases = [
+    "foo",
+    "bar"
+];
+var providers = [
+    {
+        "alias": "foo",
+        "cname": "foo.com",
+        "weight": 75
+    },
+    {
+        "alias": "bar",
+        "cname": "bar.com",
+        "weight": 25
+    }
+];
+var ttl = 20;
+
+function init(config) {
+    for (var i = 0; i < aliases.length; i++) {
+        config.requireProvider(aliases[i]);
+    }
+}
+
+function onRequest(request, response) {
+    var total = 0;
+    var i;
+    for (i = 0; i < providers.length; i++) {
+        total += providers[i].weight;
+    }
+    var pick = Math.random() * total;
+    for (i = 0; i < providers.length; i++) {
+        pick -= providers[i].weight;
+        if (pick < 0) {
+            break;
+        }
+    }
+    var chosen = providers[Math.min(i, providers.length - 1)];
+    response.respond(chosen.alias, chosen.cname);
+    response.setTTL(ttl);
+    response.setReasonCode('A');
+}